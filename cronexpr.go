@@ -27,24 +27,39 @@ import (
 
 // A Expression represents a specific cron time expression as defined at
 // <https://github.com/gorhill/cronexpr#implementation>
+//
+// An Expression is immutable once parsed, and is safe for concurrent use by
+// multiple goroutines.
 type Expression struct {
-	expression             string
-	secondList             []int
-	minuteList             []int
-	hourList               []int
-	daysOfMonth            map[int]bool
-	workdaysOfMonth        map[int]bool
-	lastDayOfMonth         bool
-	lastWorkdayOfMonth     bool
-	daysOfMonthRestricted  bool
-	actualDaysOfMonthList  []int
-	monthList              []int
-	daysOfWeek             map[int]bool
-	specificWeekDaysOfWeek map[int]bool
-	lastWeekDaysOfWeek     map[int]bool
-	daysOfWeekRestricted   bool
-	yearList               []int
-	hash                   *hash
+	expression                   string
+	format                       CronFormat
+	dayMatchMode                 DayMatchMode
+	seconds                      bitset
+	minutes                      bitset
+	hours                        bitset
+	daysOfMonth                  bitset
+	workdaysOfMonth              bitset
+	lastDayOfMonth               bool
+	daysBeforeLastDayOfMonth     bitset // bit n for `L-n`
+	lastWorkdayOfMonth           bool
+	workdaysBeforeLastDayOfMonth bitset // bit n for `L-nW`
+	daysOfMonthRestricted        bool
+	months                       bitset
+	daysOfWeek                   bitset
+	specificWeekDaysOfWeek       bitset // bit (week-1)*7+dow for `dow#week`
+	lastWeekDaysOfWeek           bitset
+	daysOfWeekRestricted         bool
+	yearList                     []int          // nil if all years match
+	every                        time.Duration  // non-zero for `@every` interval schedules
+	everyPhase                   time.Duration  // offset of `@every` instants from the Unix epoch
+	everyRelative                bool           // whether `@every` instants follow the given time instead
+	location                     *time.Location // nil to use the time zone of the given time
+	dstPolicy                    DSTPolicy      // with default fields left empty
+	calendars                    calendarUnion  // excluded time instants, if any
+	jitter                       time.Duration  // maximum shift of time instants, in whole seconds
+	jitterSeed                   string         // seed of the shift of each time instant
+	hash                         *hash
+	dayMasks                     *dayMaskCache
 }
 
 // ParseOption allows for modular implementation of custom parsing options of an Expression.
@@ -60,8 +75,9 @@ type ParseOption interface {
 // See <https://github.com/gorhill/cronexpr#implementation> for documentation
 // about what is a well-formed cron expression from this library's point of
 // view.
-func MustParseForFormat(format CronFormat, cronLine string) *Expression {
-	expr, err := Parse(cronLine)
+// Accepts a custom CronFormat and ParseOptions, see ParseForFormat.
+func MustParseForFormat(format CronFormat, cronLine string, options ...ParseOption) *Expression {
+	expr, err := ParseForFormat(format, cronLine, options...)
 	if err != nil {
 		panic(err)
 	}
@@ -69,8 +85,8 @@ func MustParseForFormat(format CronFormat, cronLine string) *Expression {
 }
 
 // MustParse uses MustParseForFormat with CronFormatStandard.
-func MustParse(cronLine string) *Expression {
-	return MustParseForFormat(CronFormatStandard, cronLine)
+func MustParse(cronLine string, options ...ParseOption) *Expression {
+	return MustParseForFormat(CronFormatStandard, cronLine, options...)
 }
 
 /******************************************************************************/
@@ -82,25 +98,12 @@ func MustParse(cronLine string) *Expression {
 // view.
 // Accepts a custom CronFormat, which will control parsing behaviour based on the CronFormat's implementation.
 func ParseForFormat(format CronFormat, cronLine string, options ...ParseOption) (*Expression, error) {
-	// Maybe one of the built-in aliases is being used
-	cron := cronNormalizer.Replace(cronLine)
-
-	indices := fieldFinder.FindAllStringIndex(cron, -1)
-	fieldCount := len(indices)
-	if fieldCount < 5 {
-		return nil, fmt.Errorf("missing field(s)")
-	}
-	// ignore fields beyond 7th
-	if fieldCount > 7 {
-		fieldCount = 7
-	}
-
 	// Initialise format-specific expression
 	expr, err := newFormattedExpression(format)
 	if err != nil {
 		return nil, err
 	}
-	var field = 0
+	expr.expression = cronLine
 
 	// Sort parse options by priority, smaller priority first.
 	sort.SliceStable(options, func(i, j int) bool {
@@ -110,13 +113,39 @@ func ParseForFormat(format CronFormat, cronLine string, options ...ParseOption)
 	// Apply options to given expression.
 	for _, option := range options {
 		if err := option.Apply(expr.Expression); err != nil {
-			return nil, fmt.Errorf("apply option error: %v", err)
+			return nil, fmt.Errorf("apply option error: %w", err)
+		}
+	}
+
+	// Maybe one of the built-in aliases is being used
+	cron, err := expr.lineHandler(cronLine)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interval schedules have no fields
+	if expr.every != 0 {
+		return expr.Expression, nil
+	}
+
+	indices := fieldFinder.FindAllStringIndex(cron, -1)
+	fieldCount := len(indices)
+	if fieldCount < 5 {
+		return nil, &ParseError{
+			Offset: len(cron),
+			Reason: ReasonTooFewFields,
+			Err:    fmt.Errorf("missing field(s)"),
 		}
 	}
+	// ignore fields beyond 7th
+	if fieldCount > 7 {
+		fieldCount = 7
+	}
+	var field = 0
 
 	// second field (optional)
 	if fieldCount == 7 {
-		err = expr.secondFieldHandler(cron[indices[field][0]:indices[field][1]])
+		err = parseField(cron, indices[field], secondDescriptor.name, expr.secondFieldHandler)
 		if err != nil {
 			return nil, err
 		}
@@ -127,39 +156,39 @@ func ParseForFormat(format CronFormat, cronLine string, options ...ParseOption)
 			return nil, err
 		}
 	} else {
-		expr.secondList = []int{0}
+		expr.seconds.add(0)
 	}
 
 	// minute field
-	err = expr.minuteFieldHandler(cron[indices[field][0]:indices[field][1]])
+	err = parseField(cron, indices[field], minuteDescriptor.name, expr.minuteFieldHandler)
 	if err != nil {
 		return nil, err
 	}
 	field += 1
 
 	// hour field
-	err = expr.hourFieldHandler(cron[indices[field][0]:indices[field][1]])
+	err = parseField(cron, indices[field], hourDescriptor.name, expr.hourFieldHandler)
 	if err != nil {
 		return nil, err
 	}
 	field += 1
 
 	// day of month field
-	err = expr.domFieldHandler(cron[indices[field][0]:indices[field][1]])
+	err = parseField(cron, indices[field], domDescriptor.name, expr.domFieldHandler)
 	if err != nil {
 		return nil, err
 	}
 	field += 1
 
 	// month field
-	err = expr.monthFieldHandler(cron[indices[field][0]:indices[field][1]])
+	err = parseField(cron, indices[field], monthDescriptor.name, expr.monthFieldHandler)
 	if err != nil {
 		return nil, err
 	}
 	field += 1
 
 	// day of week field
-	err = expr.dowFieldHandler(cron[indices[field][0]:indices[field][1]])
+	err = parseField(cron, indices[field], dowDescriptor.name, expr.dowFieldHandler)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +196,7 @@ func ParseForFormat(format CronFormat, cronLine string, options ...ParseOption)
 
 	// year field
 	if field < fieldCount {
-		err = expr.yearFieldHandler(cron[indices[field][0]:indices[field][1]])
+		err = parseField(cron, indices[field], yearDescriptor.name, expr.yearFieldHandler)
 		if err != nil {
 			return nil, err
 		}
@@ -179,8 +208,8 @@ func ParseForFormat(format CronFormat, cronLine string, options ...ParseOption)
 }
 
 // Parse uses ParseForFormat with CronFormatStandard.
-func Parse(cronLine string) (*Expression, error) {
-	return ParseForFormat(CronFormatStandard, cronLine)
+func Parse(cronLine string, options ...ParseOption) (*Expression, error) {
+	return ParseForFormat(CronFormatStandard, cronLine, options...)
 }
 
 /******************************************************************************/
@@ -190,6 +219,12 @@ func Parse(cronLine string) (*Expression, error) {
 //
 // The `time.Location` of the returned time instant is the same as that of
 // `fromTime`.
+// If the cron expression has a time zone of its own, given with a `CRON_TZ=`
+// prefix, its fields are matched in that time zone instead.
+// Wall clock times which are skipped or repeated due to a daylight saving time
+// change are handled as per WithDSTPolicy, and time instants excluded by a
+// calendar given with WithCalendar are skipped. Time instants are shifted as
+// per WithJitter.
 //
 // The zero value of time.Time is returned if no matching time instant exists
 // or if a `fromTime` is itself a zero value.
@@ -199,6 +234,31 @@ func (expr *Expression) Next(fromTime time.Time) time.Time {
 		return fromTime
 	}
 
+	if expr.jitter != 0 {
+		return expr.nextJittered(fromTime)
+	}
+	return expr.nextOccurrence(fromTime)
+}
+
+// nextOccurrence returns the earliest time instant following `fromTime` which
+// matches the expression, before it is shifted by its jitter.
+func (expr *Expression) nextOccurrence(fromTime time.Time) time.Time {
+	if expr.location != nil {
+		return inLocation(expr.nextIncluded(fromTime.In(expr.location)), fromTime.Location())
+	}
+	return expr.nextIncluded(fromTime)
+}
+
+// nextMatch returns the earliest time instant following `fromTime` which
+// matches the expression, regardless of its calendars.
+func (expr *Expression) nextMatch(fromTime time.Time) time.Time {
+	if expr.every != 0 {
+		return expr.nextEvery(fromTime)
+	}
+	return expr.nextWithDSTPolicy(fromTime)
+}
+
+func (expr *Expression) next(fromTime time.Time) time.Time {
 	loc := fromTime.Location()
 	t := fromTime.Add(time.Second - time.Duration(fromTime.Nanosecond())*time.Nanosecond)
 
@@ -206,33 +266,27 @@ WRAP:
 
 	// let's find the next date that satisfies condition
 	v := t.Year()
-	if i := sort.SearchInts(expr.yearList, v); i == len(expr.yearList) {
+	if year, ok := expr.nextYear(v); !ok {
 		return time.Time{}
-	} else if v != expr.yearList[i] {
-		t = time.Date(expr.yearList[i], time.Month(expr.monthList[0]), 1, 0, 0, 0, 0, loc)
+	} else if v != year {
+		t = time.Date(year, time.Month(expr.months.first()), 1, 0, 0, 0, 0, loc)
 	}
 
 	v = int(t.Month())
-	if i := sort.SearchInts(expr.monthList, v); i == len(expr.monthList) {
+	if month, ok := expr.months.next(v); !ok {
 		// try again with a new year
-		t = time.Date(t.Year()+1, time.Month(expr.monthList[0]), 1, 0, 0, 0, 0, loc)
-		goto WRAP
-	} else if v != expr.monthList[i] {
-		t = time.Date(t.Year(), time.Month(expr.monthList[i]), 1, 0, 0, 0, 0, loc)
-	}
-
-	expr.actualDaysOfMonthList = expr.calculateActualDaysOfMonth(t.Year(), int(t.Month()))
-	if len(expr.actualDaysOfMonthList) == 0 {
-		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		t = time.Date(t.Year()+1, time.Month(expr.months.first()), 1, 0, 0, 0, 0, loc)
 		goto WRAP
+	} else if v != month {
+		t = time.Date(t.Year(), time.Month(month), 1, 0, 0, 0, 0, loc)
 	}
 
 	v = t.Day()
-	if i := sort.SearchInts(expr.actualDaysOfMonthList, v); i == len(expr.actualDaysOfMonthList) {
+	if day, ok := expr.daysOfMonthMask(t.Year(), t.Month()).next(v); !ok {
 		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
 		goto WRAP
-	} else if v != expr.actualDaysOfMonthList[i] {
-		t = time.Date(t.Year(), t.Month(), expr.actualDaysOfMonthList[i], 0, 0, 0, 0, loc)
+	} else if v != day {
+		t = time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, loc)
 
 		// in San Palo, before 2019, there may be no midnight (or multiple midnights)
 		// due to DST
@@ -251,27 +305,27 @@ WRAP:
 
 	// Fast path where hours/minutes behave as expected trivially
 	v = t.Hour()
-	if i := sort.SearchInts(expr.hourList, v); i == len(expr.hourList) {
+	if hour, ok := expr.hours.next(v); !ok {
 		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
 		goto WRAP
-	} else if v != expr.hourList[i] {
-		t = time.Date(t.Year(), t.Month(), t.Day(), expr.hourList[i], expr.minuteList[0], expr.secondList[0], 0, loc)
+	} else if v != hour {
+		t = time.Date(t.Year(), t.Month(), t.Day(), hour, expr.minutes.first(), expr.seconds.first(), 0, loc)
 	}
 
 	v = t.Minute()
-	if i := sort.SearchInts(expr.minuteList, v); i == len(expr.minuteList) {
+	if minute, ok := expr.minutes.next(v); !ok {
 		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
 		goto WRAP
-	} else if v != expr.minuteList[i] {
-		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), expr.minuteList[i], expr.secondList[0], 0, loc)
+	} else if v != minute {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, expr.seconds.first(), 0, loc)
 	}
 
 	v = t.Second()
-	if i := sort.SearchInts(expr.secondList, v); i == len(expr.secondList) {
+	if second, ok := expr.seconds.next(v); !ok {
 		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
 		goto WRAP
-	} else if v != expr.secondList[i] {
-		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), expr.secondList[i], 0, loc)
+	} else if v != second {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, loc)
 	}
 
 	return t
@@ -280,7 +334,7 @@ SLOW_CLOCK:
 	// daylight saving effect is here, where odd things happen:
 	// An hour may have 60 minutes, 30 minutes or 90 minutes;
 	// partial hours may "repeat"!
-	for !sortContains(expr.hourList, t.Hour()) {
+	for !expr.hours.contains(t.Hour()) {
 		hourBefore := t.Hour()
 		t = t.Add(time.Hour)
 		if hourBefore == t.Hour() {
@@ -297,7 +351,7 @@ SLOW_CLOCK:
 		}
 	}
 
-	for !sortContains(expr.minuteList, t.Minute()) {
+	for !expr.minutes.contains(t.Minute()) {
 		hoursBefore := t.Hour()
 		t = t.Truncate(time.Minute).Add(time.Minute)
 		if hoursBefore != t.Hour() {
@@ -307,11 +361,11 @@ SLOW_CLOCK:
 
 	v = t.Second()
 	t = t.Truncate(time.Minute)
-	if i := sort.SearchInts(expr.secondList, v); i == len(expr.secondList) {
+	if second, ok := expr.seconds.next(v); !ok {
 		t = t.Add(time.Minute)
 		goto WRAP
 	} else {
-		t = t.Add(time.Duration(expr.secondList[i]) * time.Second)
+		t = t.Add(time.Duration(second) * time.Second)
 	}
 
 	return t
@@ -347,3 +401,178 @@ func (expr *Expression) NextN(fromTime time.Time, n uint) []time.Time {
 	}
 	return nextTimes
 }
+
+/******************************************************************************/
+
+// Prev returns the closest time instant immediately preceding `fromTime` which
+// matches the cron expression `expr`.
+//
+// The `time.Location` of the returned time instant is the same as that of
+// `fromTime`.
+// If the cron expression has a time zone of its own, given with a `CRON_TZ=`
+// prefix, its fields are matched in that time zone instead.
+// Wall clock times which are skipped or repeated due to a daylight saving time
+// change are handled as per WithDSTPolicy, and time instants excluded by a
+// calendar given with WithCalendar are skipped. Time instants are shifted as
+// per WithJitter.
+//
+// The zero value of time.Time is returned if no matching time instant exists
+// or if a `fromTime` is itself a zero value.
+func (expr *Expression) Prev(fromTime time.Time) time.Time {
+	// Special case
+	if fromTime.IsZero() {
+		return fromTime
+	}
+
+	if expr.jitter != 0 {
+		return expr.prevJittered(fromTime)
+	}
+	return expr.prevOccurrence(fromTime)
+}
+
+// prevOccurrence returns the latest time instant preceding `fromTime` which
+// matches the expression, before it is shifted by its jitter.
+func (expr *Expression) prevOccurrence(fromTime time.Time) time.Time {
+	if expr.location != nil {
+		return inLocation(expr.prevIncluded(fromTime.In(expr.location)), fromTime.Location())
+	}
+	return expr.prevIncluded(fromTime)
+}
+
+// prevMatch returns the latest time instant preceding `fromTime` which
+// matches the expression, regardless of its calendars.
+func (expr *Expression) prevMatch(fromTime time.Time) time.Time {
+	if expr.every != 0 {
+		return expr.prevEvery(fromTime)
+	}
+	return expr.prevWithDSTPolicy(fromTime)
+}
+
+func (expr *Expression) prev(fromTime time.Time) time.Time {
+	loc := fromTime.Location()
+	t := fromTime.Add(-time.Duration(fromTime.Nanosecond()) * time.Nanosecond)
+	if t.Equal(fromTime) {
+		t = t.Add(-time.Second)
+	}
+
+WRAP:
+
+	// let's find the previous date that satisfies condition
+	v := t.Year()
+	if year, ok := expr.prevYear(v); !ok {
+		return time.Time{}
+	} else if v != year {
+		t = time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc).Add(-time.Second)
+	}
+
+	v = int(t.Month())
+	if month, ok := expr.months.prev(v); !ok {
+		// try again with the previous year
+		t = time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, loc).Add(-time.Second)
+		goto WRAP
+	} else if v != month {
+		t = time.Date(t.Year(), time.Month(month)+1, 1, 0, 0, 0, 0, loc).Add(-time.Second)
+	}
+
+	v = t.Day()
+	if day, ok := expr.daysOfMonthMask(t.Year(), t.Month()).prev(v); !ok {
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Second)
+		goto WRAP
+	} else if v != day {
+		// the instant before the following midnight is the end of the day, even
+		// if that midnight does not exist or is repeated due to DST
+		t = time.Date(t.Year(), t.Month(), day+1, 0, 0, 0, 0, loc).Add(-time.Second)
+	}
+
+	if timeZoneInDay(t) {
+		goto SLOW_CLOCK
+	}
+
+	// Fast path where hours/minutes behave as expected trivially
+	v = t.Hour()
+	if hour, ok := expr.hours.prev(v); !ok {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Second)
+		goto WRAP
+	} else if v != hour {
+		t = time.Date(t.Year(), t.Month(), t.Day(), hour, expr.minutes.last(), expr.seconds.last(), 0, loc)
+	}
+
+	v = t.Minute()
+	if minute, ok := expr.minutes.prev(v); !ok {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Second)
+		goto WRAP
+	} else if v != minute {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, expr.seconds.last(), 0, loc)
+	}
+
+	v = t.Second()
+	if second, ok := expr.seconds.prev(v); !ok {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(-time.Second)
+		goto WRAP
+	} else if v != second {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, loc)
+	}
+
+	return t
+
+SLOW_CLOCK:
+	// daylight saving effect is here, see Next: walking backwards, we step
+	// over whole hours and minutes with absolute durations so that repeated
+	// wall clock times are visited in order, and skipped ones never are.
+	for !expr.hours.contains(t.Hour()) {
+		dayBefore := t.Day()
+		t = startOfHour(t).Add(-time.Second)
+		if t.Day() != dayBefore {
+			goto WRAP
+		}
+	}
+
+	for !expr.minutes.contains(t.Minute()) {
+		hourBefore := t.Hour()
+		t = t.Add(-time.Duration(t.Second()+1) * time.Second)
+		if hourBefore != t.Hour() {
+			goto WRAP
+		}
+	}
+
+	v = t.Second()
+	if second, ok := expr.seconds.prev(v); !ok {
+		t = t.Add(-time.Duration(v+1) * time.Second)
+		goto WRAP
+	} else {
+		t = t.Add(-time.Duration(v-second) * time.Second)
+	}
+
+	return t
+}
+
+/******************************************************************************/
+
+// PrevN returns a slice of `n` closest time instants immediately preceding
+// `fromTime` which match the cron expression `expr`.
+//
+// The time instants in the returned slice are in chronological descending
+// order. The `time.Location` of the returned time instants is the same as that
+// of `fromTime`.
+//
+// A slice with len between [0-`n`] is returned, that is, if not enough existing
+// matching time instants exist, the number of returned entries will be less
+// than `n`.
+func (expr *Expression) PrevN(fromTime time.Time, n uint) []time.Time {
+	prevTimes := make([]time.Time, 0, n)
+	if n > 0 {
+		fromTime = expr.Prev(fromTime)
+		for {
+			if fromTime.IsZero() {
+				break
+			}
+			prevTimes = append(prevTimes, fromTime)
+			n -= 1
+			if n == 0 {
+				break
+			}
+			fromTime = expr.Prev(fromTime)
+		}
+	}
+	return prevTimes
+}
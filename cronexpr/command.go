@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/furiko-io/cronexpr"
+)
+
+// command holds the options shared by all commands, and parses the command
+// line of one of them.
+type command struct {
+	name   string
+	flags  *flag.FlagSet
+	stdout io.Writer
+	stderr io.Writer
+
+	format           string
+	hashID           string
+	hashFields       bool
+	hashEmptySeconds bool
+	tz               string
+	output           string
+
+	// loc is the time zone given with --tz, or the local time zone.
+	loc *time.Location
+}
+
+func newCommand(name string, stdout, stderr io.Writer) *command {
+	c := &command{
+		name:   name,
+		flags:  flag.NewFlagSet(name, flag.ContinueOnError),
+		stdout: stdout,
+		stderr: stderr,
+	}
+	c.flags.SetOutput(stderr)
+	c.flags.StringVar(&c.format, "format", string(cronexpr.CronFormatStandard), `format of the cron expression: "standard", "quartz" or "kubernetes"`)
+	c.flags.StringVar(&c.hashID, "hash-id", "", "hash ID to replace H symbols with")
+	c.flags.BoolVar(&c.hashFields, "hash-fields", false, "hash each field separately (requires -hash-id)")
+	c.flags.BoolVar(&c.hashEmptySeconds, "hash-empty-seconds", false, "hash the seconds field if it is omitted (requires -hash-id)")
+	c.flags.StringVar(&c.tz, "tz", "", "IANA time zone to match the fields and print time values in, the local time zone if not present")
+	c.flags.StringVar(&c.output, "output", "text", `output format: "text", "json" or "csv"`)
+	return c
+}
+
+// parse parses the options and the `nargs` arguments of the command, which
+// may be given in any order, and returns false with an exit code if they are
+// invalid or help was requested.
+func (c *command) parse(args []string, nargs int, argsUsage string) ([]string, int, bool) {
+	c.flags.Usage = func() {
+		name := programName()
+		if c.name != "" {
+			name += " " + c.name
+		}
+		fmt.Fprintf(c.stderr, "usage:\n  %s [options] %s\noptions:\n", name, argsUsage)
+		c.flags.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := c.flags.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, exitOK, false
+			}
+			return nil, exitUsage, false
+		}
+		args = c.flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != nargs {
+		c.flags.Usage()
+		return nil, exitUsage, false
+	}
+
+	switch cronexpr.CronFormat(c.format) {
+	case cronexpr.CronFormatStandard, cronexpr.CronFormatQuartz, cronexpr.CronFormatKubernetes:
+	default:
+		return nil, c.usageError("unknown format: %q", c.format), false
+	}
+	switch c.output {
+	case "text", "json", "csv":
+	default:
+		return nil, c.usageError("invalid output format: %q", c.output), false
+	}
+	if c.hashID == "" && (c.hashFields || c.hashEmptySeconds) {
+		return nil, c.usageError("-hash-fields and -hash-empty-seconds require -hash-id"), false
+	}
+	c.loc = time.Local
+	if c.tz != "" {
+		loc, err := time.LoadLocation(c.tz)
+		if err != nil {
+			return nil, c.usageError("unknown time zone %s: %v", c.tz, err), false
+		}
+		c.loc = loc
+	}
+	return positional, exitOK, true
+}
+
+// usageError prints an error about the command line, and returns exitUsage.
+func (c *command) usageError(format string, a ...interface{}) int {
+	fmt.Fprintf(c.stderr, "# %s: %s\n", programName(), fmt.Sprintf(format, a...))
+	return exitUsage
+}
+
+// fail prints an error, and returns exitFailure.
+func (c *command) fail(err error) int {
+	fmt.Fprintf(c.stderr, "# %s: %s\n", programName(), err)
+	return exitFailure
+}
+
+// parseExpression parses a cron expression with the format, hash and time zone
+// options of the command.
+func (c *command) parseExpression(cronLine string) (*cronexpr.Expression, error) {
+	var options []cronexpr.ParseOption
+	if c.hashID != "" {
+		options = append(options, cronexpr.WithHash(c.hashID))
+		if c.hashFields {
+			options = append(options, cronexpr.WithHashFields())
+		}
+		if c.hashEmptySeconds {
+			options = append(options, cronexpr.WithHashEmptySeconds())
+		}
+	}
+	if c.tz != "" {
+		options = append(options, cronexpr.WithLocation(c.loc))
+	}
+	return cronexpr.ParseForFormat(cronexpr.CronFormat(c.format), cronLine, options...)
+}
+
+// print writes the result of the command in the output format: `v` as JSON,
+// `records` as CSV, or the text written by `text`.
+func (c *command) print(v interface{}, records [][]string, text func(w io.Writer)) {
+	switch c.output {
+	case "json":
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(v)
+	case "csv":
+		w := csv.NewWriter(c.stdout)
+		_ = w.WriteAll(records)
+	default:
+		text(c.stdout)
+	}
+}
+
+// errorReport is the structured form of an error parsing a cron expression.
+type errorReport struct {
+	Message   string `json:"message"`
+	Field     string `json:"field,omitempty"`
+	Offset    int    `json:"offset"`
+	Length    int    `json:"length"`
+	Directive string `json:"directive,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func newErrorReport(err error) *errorReport {
+	report := &errorReport{Message: err.Error()}
+	var perr *cronexpr.ParseError
+	if errors.As(err, &perr) {
+		report.Field = perr.Field
+		report.Offset = perr.Offset
+		report.Length = perr.Length
+		report.Directive = perr.Directive
+		report.Reason = string(perr.Reason)
+	}
+	return report
+}
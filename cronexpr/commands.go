@@ -0,0 +1,400 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/furiko-io/cronexpr"
+)
+
+const defaultLayout = "Mon, 02 Jan 2006 15:04:05 MST"
+
+const expressionUsage = `"{cron expression}"`
+
+// timesResult is the JSON output of next, prev and between.
+type timesResult struct {
+	Expression string   `json:"expression"`
+	From       string   `json:"from"`
+	To         string   `json:"to,omitempty"`
+	Times      []string `json:"times"`
+}
+
+func runNext(c *command, args []string) int {
+	return runNextOrPrev(c, args, false)
+}
+
+func runPrev(c *command, args []string) int {
+	return runNextOrPrev(c, args, true)
+}
+
+func runNextOrPrev(c *command, args []string, prev bool) int {
+	var inTimeStr, outTimeLayout string
+	var outTimeCount uint
+	c.flags.StringVar(&inTimeStr, "t", "", `whole or partial RFC3339 time value (i.e. "2006-01-02T15:04:05Z07:00") against which the cron expression is evaluated, now if not present`)
+	c.flags.UintVar(&outTimeCount, "n", 1, `number of resulting time values to output`)
+	c.flags.StringVar(&outTimeLayout, "l", defaultLayout, `Go-compliant time layout to use for outputting time value(s) as text, see <http://golang.org/pkg/time/#pkg-constants>`)
+	positional, code, ok := c.parse(args, 1, expressionUsage)
+	if !ok {
+		return code
+	}
+	cronStr := positional[0]
+
+	inTime, err := parseTime(inTimeStr, c.loc)
+	if err != nil {
+		return c.fail(err)
+	}
+	expr, err := c.parseExpression(cronStr)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if outTimeCount < 1 {
+		outTimeCount = 1
+	}
+	outTimes, op := expr.NextN(inTime, outTimeCount), "+"
+	if prev {
+		outTimes, op = expr.PrevN(inTime, outTimeCount), "-"
+	}
+
+	// Anything on the output which starts with '#' can be ignored if the caller
+	// is interested only in the time values. There is only one time
+	// value per line, and they are always in chronological ascending order,
+	// or descending order for prev.
+	header := fmt.Sprintf("# \"%s\" %s \"%s\" =", cronStr, op, inTime.Format(time.RFC3339))
+	c.printTimes(header, timesResult{Expression: cronStr, From: inTime.Format(time.RFC3339)}, outTimes, outTimeLayout)
+	return exitOK
+}
+
+func runBetween(c *command, args []string) int {
+	var fromStr, toStr, outTimeLayout string
+	var limit int
+	c.flags.StringVar(&fromStr, "from", "", `whole or partial RFC3339 time value at which the time window starts, now if not present`)
+	c.flags.StringVar(&toStr, "to", "", `whole or partial RFC3339 time value at which the time window ends (required)`)
+	c.flags.IntVar(&limit, "n", 0, `maximum number of resulting time values to output, no limit if 0`)
+	c.flags.StringVar(&outTimeLayout, "l", defaultLayout, `Go-compliant time layout to use for outputting time value(s) as text, see <http://golang.org/pkg/time/#pkg-constants>`)
+	positional, code, ok := c.parse(args, 1, expressionUsage)
+	if !ok {
+		return code
+	}
+	if toStr == "" {
+		return c.usageError("-to is required")
+	}
+	cronStr := positional[0]
+
+	fromTime, err := parseTime(fromStr, c.loc)
+	if err != nil {
+		return c.fail(err)
+	}
+	toTime, err := parseTime(toStr, c.loc)
+	if err != nil {
+		return c.fail(err)
+	}
+	expr, err := c.parseExpression(cronStr)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	header := fmt.Sprintf("# \"%s\" between \"%s\" and \"%s\" =", cronStr, fromTime.Format(time.RFC3339), toTime.Format(time.RFC3339))
+	result := timesResult{Expression: cronStr, From: fromTime.Format(time.RFC3339), To: toTime.Format(time.RFC3339)}
+	c.printTimes(header, result, expr.Between(fromTime, toTime, limit), outTimeLayout)
+	return exitOK
+}
+
+func (c *command) printTimes(header string, result timesResult, times []time.Time, layout string) {
+	result.Times = make([]string, 0, len(times))
+	records := [][]string{{"time"}}
+	for _, t := range times {
+		result.Times = append(result.Times, t.Format(time.RFC3339))
+		records = append(records, []string{t.Format(time.RFC3339)})
+	}
+	c.print(result, records, func(w io.Writer) {
+		fmt.Fprintln(w, header)
+		for _, t := range times {
+			fmt.Fprintln(w, t.Format(layout))
+		}
+	})
+}
+
+/******************************************************************************/
+
+// validation is the JSON output of validate.
+type validation struct {
+	Expression string       `json:"expression"`
+	Valid      bool         `json:"valid"`
+	Normalized string       `json:"normalized,omitempty"`
+	Error      *errorReport `json:"error,omitempty"`
+}
+
+func runValidate(c *command, args []string) int {
+	positional, code, ok := c.parse(args, 1, expressionUsage)
+	if !ok {
+		return code
+	}
+	cronStr := positional[0]
+
+	result := validation{Expression: cronStr, Valid: true}
+	expr, err := c.parseExpression(cronStr)
+	if err != nil {
+		result.Valid = false
+		result.Error = newErrorReport(err)
+	} else {
+		result.Normalized = expr.String()
+	}
+
+	records := [][]string{{"valid", "normalized", "field", "offset", "length", "directive", "reason", "message"}}
+	if result.Valid {
+		records = append(records, []string{"true", result.Normalized, "", "", "", "", "", ""})
+	} else {
+		e := result.Error
+		records = append(records, []string{"false", "", e.Field, strconv.Itoa(e.Offset), strconv.Itoa(e.Length), e.Directive, e.Reason, e.Message})
+	}
+	c.print(result, records, func(w io.Writer) {
+		if result.Valid {
+			fmt.Fprintf(w, "valid: %s\n", result.Normalized)
+			return
+		}
+		e := result.Error
+		if e.Reason != "" {
+			fmt.Fprintf(w, "invalid: %s (%s)\n", e.Message, e.Reason)
+		} else {
+			fmt.Fprintf(w, "invalid: %s\n", e.Message)
+		}
+		// Point at the offending directive, if it is in the cron line as given.
+		if e.Length > 0 && e.Offset+e.Length <= len(cronStr) && cronStr[e.Offset:e.Offset+e.Length] == e.Directive {
+			fmt.Fprintf(w, "  %s\n  %s%s\n", cronStr, strings.Repeat(" ", e.Offset), strings.Repeat("^", e.Length))
+		}
+	})
+	if !result.Valid {
+		return exitFailure
+	}
+	return exitOK
+}
+
+/******************************************************************************/
+
+// explanation is the JSON output of explain.
+type explanation struct {
+	Expression  string       `json:"expression"`
+	Format      string       `json:"format"`
+	Normalized  string       `json:"normalized"`
+	Description string       `json:"description"`
+	Fingerprint string       `json:"fingerprint"`
+	TimeZone    string       `json:"timeZone,omitempty"`
+	Fields      *fieldValues `json:"fields,omitempty"`
+}
+
+// fieldValues holds the normalized fields of an expression.
+type fieldValues struct {
+	Second     string `json:"second,omitempty"`
+	Minute     string `json:"minute"`
+	Hour       string `json:"hour"`
+	DayOfMonth string `json:"dayOfMonth"`
+	Month      string `json:"month"`
+	DayOfWeek  string `json:"dayOfWeek"`
+	Year       string `json:"year,omitempty"`
+}
+
+// field is a normalized field of an expression, with the name used by
+// cronexpr.ParseError.
+type field struct {
+	name  string
+	value string
+}
+
+func runExplain(c *command, args []string) int {
+	positional, code, ok := c.parse(args, 1, expressionUsage)
+	if !ok {
+		return code
+	}
+	cronStr := positional[0]
+	expr, err := c.parseExpression(cronStr)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	result := explanation{
+		Expression:  cronStr,
+		Format:      c.format,
+		Normalized:  expr.String(),
+		Description: expr.Describe(),
+		Fingerprint: formatFingerprint(expr),
+	}
+	if loc := expr.Location(); loc != nil {
+		result.TimeZone = loc.String()
+	}
+	fields := normalizedFields(expr)
+	if fields != nil {
+		result.Fields = &fieldValues{}
+		for _, f := range fields {
+			*result.Fields.value(f.name) = f.value
+		}
+	}
+
+	properties := []field{
+		{"expression", result.Expression},
+		{"format", result.Format},
+		{"normalized", result.Normalized},
+		{"description", result.Description},
+		{"fingerprint", result.Fingerprint},
+	}
+	if result.TimeZone != "" {
+		properties = append(properties, field{"time-zone", result.TimeZone})
+	}
+	properties = append(properties, fields...)
+
+	records := [][]string{{"name", "value"}}
+	for _, p := range properties {
+		records = append(records, []string{p.name, p.value})
+	}
+	c.print(result, records, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, p := range properties {
+			fmt.Fprintf(tw, "%s:\t%s\n", p.name, p.value)
+		}
+		tw.Flush()
+	})
+	return exitOK
+}
+
+// value returns the field of fieldValues with the given name.
+func (v *fieldValues) value(name string) *string {
+	switch name {
+	case "second":
+		return &v.Second
+	case "minute":
+		return &v.Minute
+	case "hour":
+		return &v.Hour
+	case "day-of-month":
+		return &v.DayOfMonth
+	case "month":
+		return &v.Month
+	case "day-of-week":
+		return &v.DayOfWeek
+	default:
+		return &v.Year
+	}
+}
+
+func formatFingerprint(expr *cronexpr.Expression) string {
+	return fmt.Sprintf("%016x", expr.Fingerprint())
+}
+
+// normalizedFields returns the fields of the normalized form of `expr`, or
+// nil for an `@every` interval schedule.
+func normalizedFields(expr *cronexpr.Expression) []field {
+	normalized := expr.String()
+	if strings.HasPrefix(normalized, "CRON_TZ=") {
+		normalized = normalized[strings.IndexByte(normalized, ' ')+1:]
+	}
+	if strings.HasPrefix(normalized, "@") {
+		return nil
+	}
+
+	names := []string{"second", "minute", "hour", "day-of-month", "month", "day-of-week", "year"}
+	values := strings.Fields(normalized)
+	if len(values) == 5 {
+		names = names[1:6]
+	}
+	fields := make([]field, len(values))
+	for i, value := range values {
+		fields[i] = field{name: names[i], value: value}
+	}
+	return fields
+}
+
+/******************************************************************************/
+
+// comparison is the JSON output of diff.
+type comparison struct {
+	Equal       bool         `json:"equal"`
+	Left        summary      `json:"left"`
+	Right       summary      `json:"right"`
+	Differences []difference `json:"differences"`
+}
+
+type summary struct {
+	Expression  string `json:"expression"`
+	Normalized  string `json:"normalized"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type difference struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+func runDiff(c *command, args []string) int {
+	positional, code, ok := c.parse(args, 2, expressionUsage+" "+expressionUsage)
+	if !ok {
+		return code
+	}
+	var exprs [2]*cronexpr.Expression
+	var summaries [2]summary
+	for i, cronStr := range positional {
+		expr, err := c.parseExpression(cronStr)
+		if err != nil {
+			return c.fail(err)
+		}
+		exprs[i] = expr
+		summaries[i] = summary{Expression: cronStr, Normalized: expr.String(), Fingerprint: formatFingerprint(expr)}
+	}
+
+	result := comparison{Equal: exprs[0].Equal(exprs[1]), Left: summaries[0], Right: summaries[1], Differences: []difference{}}
+	if !result.Equal {
+		result.Differences = differences(exprs[0], exprs[1])
+	}
+
+	records := [][]string{{"field", "left", "right"}}
+	for _, d := range result.Differences {
+		records = append(records, []string{d.Field, d.Left, d.Right})
+	}
+	c.print(result, records, func(w io.Writer) {
+		fmt.Fprintf(w, "--- %s\n+++ %s\n", result.Left.Normalized, result.Right.Normalized)
+		if result.Equal {
+			fmt.Fprintln(w, "equal")
+		}
+		for _, d := range result.Differences {
+			fmt.Fprintf(w, "%s: %s -> %s\n", d.Field, d.Left, d.Right)
+		}
+	})
+	if !result.Equal {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// differences returns the normalized fields which differ between two
+// expressions, or their whole normalized forms if their fields cannot be
+// compared one by one.
+func differences(left, right *cronexpr.Expression) []difference {
+	var diffs []difference
+	leftLoc, rightLoc := "", ""
+	if loc := left.Location(); loc != nil {
+		leftLoc = loc.String()
+	}
+	if loc := right.Location(); loc != nil {
+		rightLoc = loc.String()
+	}
+	if leftLoc != rightLoc {
+		diffs = append(diffs, difference{Field: "time-zone", Left: leftLoc, Right: rightLoc})
+	}
+
+	leftFields, rightFields := normalizedFields(left), normalizedFields(right)
+	if leftFields != nil && len(leftFields) == len(rightFields) {
+		for i := range leftFields {
+			if leftFields[i].value != rightFields[i].value {
+				diffs = append(diffs, difference{Field: leftFields[i].name, Left: leftFields[i].value, Right: rightFields[i].value})
+			}
+		}
+	}
+	if len(diffs) == 0 || leftFields == nil || len(leftFields) != len(rightFields) {
+		diffs = append(diffs, difference{Field: "schedule", Left: left.String(), Right: right.String()})
+	}
+	return diffs
+}
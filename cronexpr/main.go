@@ -13,9 +13,11 @@ package main
 /******************************************************************************/
 
 import (
-	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/furiko-io/cronexpr"
@@ -23,34 +25,81 @@ import (
 
 /******************************************************************************/
 
+// Exit codes
+const (
+	// exitOK is returned on success, when an expression is valid, and when
+	// diff finds expressions to be equal.
+	exitOK = 0
+
+	// exitFailure is returned when an expression or a time value is invalid,
+	// and when diff finds expressions to differ.
+	exitFailure = 1
+
+	// exitUsage is returned when the command line is invalid.
+	exitUsage = 2
+)
+
 var (
-	usage = func() {
-		fmt.Fprintf(os.Stderr, "usage:\n  %s [options] \"{cron expression}\"\noptions:\n", os.Args[0])
-		flag.PrintDefaults()
+	// clock tells the time which the cron expression is evaluated against
+	// when none is given.
+	clock cronexpr.Clock = cronexpr.RealClock{}
+
+	commands = map[string]func(c *command, args []string) int{
+		"next":     runNext,
+		"prev":     runPrev,
+		"between":  runBetween,
+		"validate": runValidate,
+		"explain":  runExplain,
+		"diff":     runDiff,
 	}
-	inTimeStr     string
-	outTimeCount  uint
-	outTimeLayout string
 )
 
 /******************************************************************************/
 
 func main() {
-	var err error
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-	flag.Usage = usage
-	flag.StringVar(&inTimeStr, "t", "", `whole or partial RFC3339 time value (i.e. "2006-01-02T15:04:05Z07:00") against which the cron expression is evaluated, now if not present`)
-	flag.UintVar(&outTimeCount, "n", 1, `number of resulting time values to output`)
-	flag.StringVar(&outTimeLayout, "l", "Mon, 02 Jan 2006 15:04:05 MST", `Go-compliant time layout to use for outputting time value(s), see <http://golang.org/pkg/time/#pkg-constants>`)
-	flag.Parse()
+// run runs the command line `args`, without the program name, and returns the
+// exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if fn, ok := commands[args[0]]; ok {
+			return fn(newCommand(args[0], stdout, stderr), args[1:])
+		}
+	}
+	if len(args) == 0 {
+		usage(stderr)
+		return exitOK
+	}
 
-	cronStr := flag.Arg(0)
-	if len(cronStr) == 0 {
-		flag.Usage()
-		return
+	// Without a command, options and a cron expression are evaluated as with
+	// `next`, as in earlier versions.
+	return runNext(newCommand("", stdout, stderr), args)
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "usage:\n  %s [command] [options] \"{cron expression}\"\ncommands:\n  %s\n", programName(), strings.Join(names, "\n  "))
+	fmt.Fprintf(w, "Without a command, next is run. Use \"%s {command} -h\" for the options of a command.\n", programName())
+}
 
-	inTime := time.Now()
+func programName() string {
+	if len(os.Args) == 0 {
+		return "cronexpr"
+	}
+	return os.Args[0]
+}
+
+/******************************************************************************/
+
+// parseTime parses a whole or partial RFC3339 time value, in `loc` unless it
+// has a time zone offset. An empty value is the current time.
+func parseTime(inTimeStr string, loc *time.Location) (time.Time, error) {
 	inTimeLayout := ""
 	timeStrLen := len(inTimeStr)
 	if timeStrLen == 2 {
@@ -77,35 +126,23 @@ func main() {
 		}
 	}
 
-	if len(inTimeLayout) > 0 {
-		// default to local time zone
-		if timeStrLen < 20 {
-			inTime, err = time.ParseInLocation(inTimeLayout, inTimeStr, time.Local)
-		} else {
-			inTime, err = time.Parse(inTimeLayout, inTimeStr)
-		}
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "# error: unparseable time value: \"%s\"\n", inTimeStr)
-			os.Exit(1)
+	if len(inTimeLayout) == 0 {
+		if timeStrLen > 0 {
+			return time.Time{}, fmt.Errorf("unparseable time value: \"%s\"", inTimeStr)
 		}
+		return clock.Now().In(loc), nil
 	}
 
-	expr, err := cronexpr.Parse(cronStr)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "# %s: %s\n", os.Args[0], err)
-		os.Exit(1)
-	}
-
-	// Anything on the output which starts with '#' can be ignored if the caller
-	// is interested only in the time values. There is only one time
-	// value per line, and they are always in chronological ascending order.
-	fmt.Printf("# \"%s\" + \"%s\" =\n", cronStr, inTime.Format(time.RFC3339))
-
-	if outTimeCount < 1 {
-		outTimeCount = 1
+	var inTime time.Time
+	var err error
+	// default to local time zone
+	if timeStrLen < 20 {
+		inTime, err = time.ParseInLocation(inTimeLayout, inTimeStr, loc)
+	} else {
+		inTime, err = time.Parse(inTimeLayout, inTimeStr)
 	}
-	outTimes := expr.NextN(inTime, outTimeCount)
-	for _, outTime := range outTimes {
-		fmt.Println(outTime.Format(outTimeLayout))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unparseable time value: \"%s\"", inTimeStr)
 	}
+	return inTime, nil
 }
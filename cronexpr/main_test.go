@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/furiko-io/cronexpr"
+)
+
+func TestRun(t *testing.T) {
+	clock = cronexpr.NewFakeClock(time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC))
+	defer func() {
+		clock = cronexpr.RealClock{}
+	}()
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name: "without a command",
+			args: []string{"-t=2013-08-31T00:00:00Z", "-n=2", "0 0 31 12 *"},
+			want: "# \"0 0 31 12 *\" + \"2013-08-31T00:00:00Z\" =\nTue, 31 Dec 2013 00:00:00 UTC\nWed, 31 Dec 2014 00:00:00 UTC\n",
+		},
+		{
+			name: "next at the current time",
+			args: []string{"next", "--tz", "UTC", "-l", time.RFC3339, "0 0 * * *"},
+			want: "# \"0 0 * * *\" + \"2021-03-01T12:00:00Z\" =\n2021-03-02T00:00:00Z\n",
+		},
+		{
+			name: "next with options after the expression",
+			args: []string{"next", "H 9 * * *", "--hash-id", "my-job", "--tz", "Asia/Tokyo", "--output", "json"},
+			want: `{
+  "expression": "H 9 * * *",
+  "from": "2021-03-01T21:00:00+09:00",
+  "times": [
+    "2021-03-02T09:46:00+09:00"
+  ]
+}
+`,
+		},
+		{
+			name: "prev",
+			args: []string{"prev", "--tz", "UTC", "-n", "2", "--output", "csv", "--format", "quartz", "0 0 12 ? * MON-FRI *"},
+			want: "time\n2021-02-26T12:00:00Z\n2021-02-25T12:00:00Z\n",
+		},
+		{
+			name: "between",
+			args: []string{"between", "--from", "2021-03-01T00:00:00Z", "--to", "2021-03-01T03:00:00Z", "--output", "csv", "0 * * * *"},
+			want: "time\n2021-03-01T01:00:00Z\n2021-03-01T02:00:00Z\n2021-03-01T03:00:00Z\n",
+		},
+		{
+			name: "valid",
+			args: []string{"validate", "--hash-id", "my-job", "--hash-empty-seconds", "H 9 * * *"},
+			want: "valid: 46 46 9 * * * *\n",
+		},
+		{
+			name:     "invalid",
+			args:     []string{"validate", "0 0 1,15X * *"},
+			wantCode: exitFailure,
+			want:     "invalid: syntax error in day-of-month field: '15X' (unknown-token)\n  0 0 1,15X * *\n        ^^^\n",
+		},
+		{
+			name:     "invalid as csv",
+			args:     []string{"validate", "--output", "csv", "0 0 1,15X * *"},
+			wantCode: exitFailure,
+			want:     "valid,normalized,field,offset,length,directive,reason,message\nfalse,,day-of-month,6,3,15X,unknown-token,syntax error in day-of-month field: '15X'\n",
+		},
+		{
+			name: "explain",
+			args: []string{"explain", "--format", "kubernetes", "CRON_TZ=Asia/Tokyo 0 9 * * 1-5"},
+			want: `expression:    CRON_TZ=Asia/Tokyo 0 9 * * 1-5
+format:        kubernetes
+normalized:    CRON_TZ=Asia/Tokyo 0 9 * * 1-5
+description:   At 09:00 on Monday through Friday, in Asia/Tokyo time
+fingerprint:   ` + formatFingerprint(cronexpr.MustParseForFormat(cronexpr.CronFormatKubernetes, "CRON_TZ=Asia/Tokyo 0 9 * * 1-5")) + `
+time-zone:     Asia/Tokyo
+minute:        0
+hour:          9
+day-of-month:  *
+month:         *
+day-of-week:   1-5
+`,
+		},
+		{
+			name: "equal",
+			args: []string{"diff", "*/15 9-17 * * 1-5", "0,15,30,45 9-17 * * mon-fri"},
+			want: "--- 0 */15 9-17 * * 1-5 *\n+++ 0 */15 9-17 * * 1-5 *\nequal\n",
+		},
+		{
+			name:     "different",
+			args:     []string{"diff", "0 9 * * 1-5", "CRON_TZ=UTC 0 9 * * *"},
+			wantCode: exitFailure,
+			want:     "--- 0 0 9 * * 1-5 *\n+++ CRON_TZ=UTC 0 0 9 * * * *\ntime-zone:  -> UTC\nday-of-week: 1-5 -> *\n",
+		},
+		{
+			name:     "different kinds of schedules",
+			args:     []string{"diff", "--output", "csv", "@every 1h", "0 * * * *"},
+			wantCode: exitFailure,
+			want:     "field,left,right\nschedule,@every 1h0m0s,0 0 * * * * *\n",
+		},
+		{
+			name:     "invalid expression",
+			args:     []string{"next", "0 0 1,15X * *"},
+			wantCode: exitFailure,
+		},
+		{
+			name:     "invalid time",
+			args:     []string{"next", "-t", "2021-13", "0 0 * * *"},
+			wantCode: exitFailure,
+		},
+		{
+			name:     "missing expression",
+			args:     []string{"explain"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown option",
+			args:     []string{"validate", "--unknown", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown format",
+			args:     []string{"validate", "--format", "other", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown output format",
+			args:     []string{"validate", "--output", "xml", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "hash options without a hash ID",
+			args:     []string{"validate", "--hash-fields", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown time zone",
+			args:     []string{"next", "--tz", "Mars/Olympus_Mons", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "between without an end",
+			args:     []string{"between", "0 0 * * *"},
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, &stdout, &stderr)
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			assert.Equal(t, tt.want, stdout.String())
+			if tt.wantCode != exitOK && tt.want == "" {
+				assert.NotEmpty(t, stderr.String())
+			}
+		})
+	}
+}
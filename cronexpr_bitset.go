@@ -0,0 +1,89 @@
+package cronexpr
+
+import (
+	"math/bits"
+)
+
+// bitset is a set of values between 0 and 63, where bit n is set if the value
+// n is in the set. Lookups of the next or previous value in the set are done
+// with a single bit operation.
+type bitset uint64
+
+// newBitset returns a bitset of a list of values.
+func newBitset(list []int) bitset {
+	var b bitset
+	for _, v := range list {
+		b.add(v)
+	}
+	return b
+}
+
+func (b *bitset) add(v int) {
+	*b |= 1 << uint(v)
+}
+
+func (b *bitset) addRange(first, last, step int) {
+	for v := first; v <= last; v += step {
+		b.add(v)
+	}
+}
+
+func (b bitset) contains(v int) bool {
+	return v >= 0 && v < 64 && b&(1<<uint(v)) != 0
+}
+
+func (b bitset) count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// first returns the smallest value in the set, or 64 if it is empty.
+func (b bitset) first() int {
+	return bits.TrailingZeros64(uint64(b))
+}
+
+// last returns the largest value in the set, or -1 if it is empty.
+func (b bitset) last() int {
+	return 63 - bits.LeadingZeros64(uint64(b))
+}
+
+// next returns the smallest value in the set which is not less than `v`, and
+// false if there is none.
+func (b bitset) next(v int) (int, bool) {
+	if v < 0 {
+		v = 0
+	}
+	if v > 63 {
+		return 0, false
+	}
+	rest := b >> uint(v)
+	if rest == 0 {
+		return 0, false
+	}
+	return v + bits.TrailingZeros64(uint64(rest)), true
+}
+
+// prev returns the largest value in the set which is not greater than `v`,
+// and false if there is none.
+func (b bitset) prev(v int) (int, bool) {
+	if v < 0 {
+		return 0, false
+	}
+	if v < 63 {
+		b &= 1<<uint(v+1) - 1
+	}
+	if b == 0 {
+		return 0, false
+	}
+	return b.last(), true
+}
+
+// list returns the values in the set in ascending order.
+func (b bitset) list() []int {
+	list := make([]int, 0, b.count())
+	for b != 0 {
+		v := b.first()
+		list = append(list, v)
+		b &^= 1 << uint(v)
+	}
+	return list
+}
@@ -0,0 +1,93 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitset(t *testing.T) {
+	b := newBitset([]int{0, 5, 6, 31, 59})
+
+	assert.Equal(t, 5, b.count())
+	assert.Equal(t, 0, b.first())
+	assert.Equal(t, 59, b.last())
+	assert.Equal(t, []int{0, 5, 6, 31, 59}, b.list())
+	assert.True(t, b.contains(31))
+	assert.False(t, b.contains(30))
+	assert.False(t, b.contains(-1))
+	assert.False(t, b.contains(64))
+
+	tests := []struct {
+		v      int
+		next   int
+		nextOK bool
+		prev   int
+		prevOK bool
+	}{
+		{v: -1, next: 0, nextOK: true, prevOK: false},
+		{v: 0, next: 0, nextOK: true, prev: 0, prevOK: true},
+		{v: 1, next: 5, nextOK: true, prev: 0, prevOK: true},
+		{v: 6, next: 6, nextOK: true, prev: 6, prevOK: true},
+		{v: 32, next: 59, nextOK: true, prev: 31, prevOK: true},
+		{v: 60, nextOK: false, prev: 59, prevOK: true},
+		{v: 64, nextOK: false, prev: 59, prevOK: true},
+	}
+	for _, tt := range tests {
+		next, ok := b.next(tt.v)
+		assert.Equal(t, tt.nextOK, ok, "next(%d)", tt.v)
+		if ok {
+			assert.Equal(t, tt.next, next, "next(%d)", tt.v)
+		}
+		prev, ok := b.prev(tt.v)
+		assert.Equal(t, tt.prevOK, ok, "prev(%d)", tt.v)
+		if ok {
+			assert.Equal(t, tt.prev, prev, "prev(%d)", tt.v)
+		}
+	}
+
+	var empty bitset
+	_, ok := empty.next(0)
+	assert.False(t, ok)
+	_, ok = empty.prev(63)
+	assert.False(t, ok)
+	assert.Empty(t, empty.list())
+
+	var r bitset
+	r.addRange(1, 31, 5)
+	assert.Equal(t, []int{1, 6, 11, 16, 21, 26, 31}, r.list())
+}
+
+func TestExpression_daysOfMonthMask(t *testing.T) {
+	tests := []struct {
+		expr  string
+		year  int
+		month int
+		want  []int
+	}{
+		{expr: "0 0 * * *", year: 2021, month: 2, want: genericDefaultList[1:29]},
+		{expr: "0 0 * * *", year: 2024, month: 2, want: genericDefaultList[1:30]},
+		{expr: "0 0 31 * *", year: 2021, month: 4, want: []int{}},
+		{expr: "0 0 L * *", year: 2021, month: 4, want: []int{30}},
+		{expr: "0 0 LW * *", year: 2021, month: 10, want: []int{29}},
+		{expr: "0 0 1W * *", year: 2021, month: 5, want: []int{3}},
+		{expr: "0 0 31W * *", year: 2021, month: 10, want: []int{29}},
+		{expr: "0 0 L-2 * *", year: 2021, month: 2, want: []int{26}},
+		{expr: "0 0 L-2W * *", year: 2021, month: 2, want: []int{26}},
+		{expr: "0 0 L-1W * *", year: 2021, month: 10, want: []int{29}},
+		{expr: "0 0 L-30W * *", year: 2021, month: 2, want: []int{}},
+		{expr: "0 0 * * 1", year: 2021, month: 11, want: []int{1, 8, 15, 22, 29}},
+		{expr: "0 0 * * 2#5", year: 2021, month: 11, want: []int{30}},
+		{expr: "0 0 * * 3#5", year: 2021, month: 11, want: []int{}},
+		{expr: "0 0 * * 0L,6L", year: 2021, month: 7, want: []int{25, 31}},
+		{expr: "0 0 13 * 5", year: 2021, month: 8, want: []int{6, 13, 20, 27}},
+	}
+	for _, tt := range tests {
+		expr := MustParse(tt.expr)
+		// The second lookup is served from the cache.
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, tt.want, expr.daysOfMonthMask(tt.year, time.Month(tt.month)).list(), "%s in %d-%02d", tt.expr, tt.year, tt.month)
+		}
+	}
+}
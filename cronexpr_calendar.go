@@ -0,0 +1,327 @@
+package cronexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Calendar is a set of excluded time spans, such as public holidays, in the
+// style of the calendars of the Quartz scheduler. An Expression skips the time
+// instants excluded by the calendars given with WithCalendar.
+type Calendar interface {
+	// Exclusion returns the span of excluded time instants which `t` is in,
+	// from `start` up to but not including `end`, and true, or false if `t`
+	// is not excluded. A zero `start` or `end` means that the span has no
+	// beginning or no end.
+	Exclusion(t time.Time) (start, end time.Time, ok bool)
+}
+
+// WithCalendar returns a ParseOption that excludes the time instants of the
+// given calendars from the Expression, such that Next, Prev and Iter skip them.
+// Dates are in the time zone that the fields are matched in.
+//
+// Calendars are not part of the Spec or String of an Expression, but they are
+// compared by Equal, see canonicalCalendar.
+func WithCalendar(calendars ...Calendar) ParseOption {
+	return &calendarParseOption{calendars: calendars}
+}
+
+type calendarParseOption struct {
+	*baseOption
+	calendars []Calendar
+}
+
+func (o *calendarParseOption) Apply(expr *Expression) error {
+	for _, calendar := range o.calendars {
+		if calendar == nil {
+			return fmt.Errorf("nil Calendar")
+		}
+	}
+	expr.calendars = append(append(calendarUnion(nil), expr.calendars...), o.calendars...)
+	return nil
+}
+
+// ExceptCalendar returns a Schedule made up of the time instants of `schedule`
+// which are not excluded by any of the given calendars, with dates in the time
+// zone of the time given to Next.
+func ExceptCalendar(schedule Schedule, calendars ...Calendar) Schedule {
+	return &calendarSchedule{schedule: schedule, calendars: calendars}
+}
+
+type calendarSchedule struct {
+	schedule  Schedule
+	calendars calendarUnion
+}
+
+func (s *calendarSchedule) Next(fromTime time.Time) time.Time {
+	return s.calendars.next(fromTime, s.schedule.Next)
+}
+
+// calendarUnion excludes the time instants excluded by any of its calendars.
+type calendarUnion []Calendar
+
+// Exclusion returns the widest span among those of the calendars excluding `t`.
+func (u calendarUnion) Exclusion(t time.Time) (start, end time.Time, ok bool) {
+	for _, calendar := range u {
+		s, e, excluded := calendar.Exclusion(t)
+		if !excluded {
+			continue
+		}
+		if !ok || s.IsZero() || (!start.IsZero() && s.Before(start)) {
+			start = s
+		}
+		if !ok || e.IsZero() || (!end.IsZero() && e.After(end)) {
+			end = e
+		}
+		ok = true
+	}
+	return start, end, ok
+}
+
+// next returns the earliest time instant given by `next` following `fromTime`
+// which is not excluded, by going past the end of each span of excluded time
+// instants in turn.
+func (u calendarUnion) next(fromTime time.Time, next func(time.Time) time.Time) time.Time {
+	t := next(fromTime)
+	for step := 0; step < maxScheduleSteps && !t.IsZero(); step++ {
+		_, end, ok := u.Exclusion(t)
+		if !ok {
+			return t
+		}
+		if end.IsZero() {
+			break
+		}
+		t = next(end.Add(-time.Nanosecond))
+	}
+	return time.Time{}
+}
+
+// prev returns the latest time instant given by `prev` preceding `fromTime`
+// which is not excluded, see next.
+func (u calendarUnion) prev(fromTime time.Time, prev func(time.Time) time.Time) time.Time {
+	t := prev(fromTime)
+	for step := 0; step < maxScheduleSteps && !t.IsZero(); step++ {
+		start, _, ok := u.Exclusion(t)
+		if !ok {
+			return t
+		}
+		if start.IsZero() {
+			break
+		}
+		t = prev(start)
+	}
+	return time.Time{}
+}
+
+// nextIncluded returns the earliest time instant following `fromTime` which
+// matches the expression and is not excluded by its calendars.
+func (expr *Expression) nextIncluded(fromTime time.Time) time.Time {
+	if len(expr.calendars) == 0 {
+		return expr.nextMatch(fromTime)
+	}
+	return expr.calendars.next(fromTime, expr.nextMatch)
+}
+
+// prevIncluded returns the latest time instant preceding `fromTime` which
+// matches the expression and is not excluded by its calendars.
+func (expr *Expression) prevIncluded(fromTime time.Time) time.Time {
+	if len(expr.calendars) == 0 {
+		return expr.prevMatch(fromTime)
+	}
+	return expr.calendars.prev(fromTime, expr.prevMatch)
+}
+
+/******************************************************************************/
+
+// Date is a calendar date. A Date with a zero Year recurs every year.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// ParseDate parses a date in the `2006-01-02` form, or a date recurring every
+// year in the `01-02` form.
+func ParseDate(s string) (Date, error) {
+	recurring := len(s) == len("01-02")
+	if recurring {
+		// 2000 is a leap year, so that February 29 is allowed.
+		s = "2000-" + s
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date: %v", err)
+	}
+	d := dateOf(t)
+	if recurring {
+		d.Year = 0
+	}
+	return d, nil
+}
+
+// String returns the date in the form accepted by ParseDate.
+func (d Date) String() string {
+	if d.Year == 0 {
+		return fmt.Sprintf("%02d-%02d", d.Month, d.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// in returns the Date in the given year, if it recurs every year.
+func (d Date) in(year int) Date {
+	if d.Year == 0 {
+		d.Year = year
+	}
+	return d
+}
+
+// before returns whether the Date is before `other`, comparing months and
+// days only if either of them recurs every year.
+func (d Date) before(other Date) bool {
+	if d.Year != 0 && other.Year != 0 && d.Year != other.Year {
+		return d.Year < other.Year
+	}
+	if d.Month != other.Month {
+		return d.Month < other.Month
+	}
+	return d.Day < other.Day
+}
+
+// midnight returns the start of the Date in `loc`, which must not recur every year.
+func (d Date) midnight(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// lastDayOfMonth returns the last day of the month of the Date, which must not
+// recur every year.
+func (d Date) lastDayOfMonth() int {
+	return time.Date(d.Year, d.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func dateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// canonicalCalendar returns a representation of the time spans excluded by a
+// Calendar created by this package, which is the same for calendars excluding
+// the same dates or schedule. Other calendars are represented by their
+// identity, or by their value if they are not pointers.
+func canonicalCalendar(c Calendar) string {
+	switch c := c.(type) {
+	case calendarUnion:
+		items := make([]string, len(c))
+		for i, calendar := range c {
+			items[i] = canonicalCalendar(calendar)
+		}
+		sort.Strings(items)
+		return "(" + strings.Join(items, " ") + ")"
+	case *dateCalendar:
+		dates := make([]string, 0, len(c.dates))
+		for d := range c.dates {
+			dates = append(dates, d.String())
+		}
+		sort.Strings(dates)
+		return "dates=" + strings.Join(dates, ",")
+	case *dateRangeCalendar:
+		return "range=" + c.first.String() + "/" + c.last.String()
+	case *cronCalendar:
+		if expr, ok := c.schedule.(*Expression); ok {
+			return "cron=(" + expr.canonical() + ")"
+		}
+	}
+	if v := reflect.ValueOf(c); v.Kind() == reflect.Ptr {
+		return fmt.Sprintf("%T@%x", c, v.Pointer())
+	}
+	return fmt.Sprintf("%#v", c)
+}
+
+/******************************************************************************/
+
+// DateCalendar returns a Calendar which excludes whole days, as the
+// HolidayCalendar and AnnualCalendar of Quartz do. Dates with a zero Year
+// are excluded every year.
+func DateCalendar(dates ...Date) Calendar {
+	c := &dateCalendar{dates: map[Date]bool{}}
+	for _, d := range dates {
+		c.dates[d] = true
+	}
+	return c
+}
+
+type dateCalendar struct {
+	dates map[Date]bool
+}
+
+func (c *dateCalendar) Exclusion(t time.Time) (start, end time.Time, ok bool) {
+	d := dateOf(t)
+	if !c.dates[d] && !c.dates[Date{Month: d.Month, Day: d.Day}] {
+		return time.Time{}, time.Time{}, false
+	}
+	start = d.midnight(t.Location())
+	return start, start.AddDate(0, 0, 1), true
+}
+
+// DateRangeCalendar returns a Calendar which excludes the days from `first` up
+// to and including `last`. If both dates have a zero Year, the range is
+// excluded every year, and may span the end of the year, e.g. from December 24
+// to January 1.
+func DateRangeCalendar(first, last Date) (Calendar, error) {
+	if (first.Year == 0) != (last.Year == 0) {
+		return nil, fmt.Errorf("date range %v to %v mixes a date recurring every year with a fixed one", first, last)
+	}
+	if first.Year != 0 && last.before(first) {
+		return nil, fmt.Errorf("date range %v to %v ends before it begins", first, last)
+	}
+	return &dateRangeCalendar{first: first, last: last}, nil
+}
+
+type dateRangeCalendar struct {
+	first, last Date
+}
+
+func (c *dateRangeCalendar) Exclusion(t time.Time) (start, end time.Time, ok bool) {
+	d := dateOf(t)
+	first, last := c.first.in(d.Year), c.last.in(d.Year)
+	if c.first.Year == 0 && last.before(first) {
+		// The range spans the end of the year, so `d` is either in the
+		// range starting the year before, or in the one ending the year after.
+		if d.before(first) {
+			first.Year--
+		} else {
+			last.Year++
+		}
+	}
+	// February 29 starts a range on March 1 and ends it on February 28 in
+	// years other than leap years.
+	first = dateOf(first.midnight(time.UTC))
+	if lastDay := last.lastDayOfMonth(); last.Day > lastDay {
+		last.Day = lastDay
+	}
+	if d.before(first) || last.before(d) {
+		return time.Time{}, time.Time{}, false
+	}
+	return first.midnight(t.Location()), last.midnight(t.Location()).AddDate(0, 0, 1), true
+}
+
+// CronCalendar returns a Calendar which excludes the time instants of a
+// schedule, such as an Expression, as the CronCalendar of Quartz does.
+// Each time instant excludes a whole second.
+func CronCalendar(schedule Schedule) Calendar {
+	return &cronCalendar{schedule: schedule}
+}
+
+type cronCalendar struct {
+	schedule Schedule
+}
+
+func (c *cronCalendar) Exclusion(t time.Time) (start, end time.Time, ok bool) {
+	start = t.Truncate(time.Second)
+	if !nextOrSame(c.schedule, start).Equal(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.Add(time.Second), true
+}
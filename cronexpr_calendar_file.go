@@ -0,0 +1,190 @@
+package cronexpr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// ReadCalendar reads a Calendar of excluded dates, either from an iCalendar
+// (RFC 5545) file or from a CSV file.
+//
+// In an iCalendar file, each VEVENT excludes the days from its DTSTART up to
+// its DTEND, or the day of its DTSTART only if it has no DTEND. A VEVENT with
+// an RRULE of FREQ=YEARLY is excluded every year. Other properties are ignored.
+//
+// In a CSV file, each record is a date, optionally followed by the last date of
+// a range and by a description, such as `2021-12-24,2021-12-26,Christmas`.
+// Dates are in the form accepted by ParseDate, so that `12-25` is excluded
+// every year. Blank lines and lines starting with `#` are ignored.
+func ReadCalendar(r io.Reader) (Calendar, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("BEGIN:VCALENDAR")) {
+		return readICalendar(data)
+	}
+	return readCSVCalendar(data)
+}
+
+// calendarBuilder collects single dates and date ranges into a Calendar.
+type calendarBuilder struct {
+	dates     []Date
+	calendars calendarUnion
+}
+
+func (b *calendarBuilder) add(first, last Date) error {
+	if first == last {
+		b.dates = append(b.dates, first)
+		return nil
+	}
+	calendar, err := DateRangeCalendar(first, last)
+	if err != nil {
+		return err
+	}
+	b.calendars = append(b.calendars, calendar)
+	return nil
+}
+
+func (b *calendarBuilder) calendar() Calendar {
+	return append(calendarUnion{DateCalendar(b.dates...)}, b.calendars...)
+}
+
+func readCSVCalendar(data []byte) (Calendar, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var b calendarBuilder
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		first, err := ParseDate(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", n, err)
+		}
+		last := first
+		if len(record) > 1 {
+			// The second field is either the last date or a description.
+			if d, err := ParseDate(strings.TrimSpace(record[1])); err == nil {
+				last = d
+			}
+		}
+		if err := b.add(first, last); err != nil {
+			return nil, fmt.Errorf("record %d: %v", n, err)
+		}
+	}
+	return b.calendar(), nil
+}
+
+func readICalendar(data []byte) (Calendar, error) {
+	var (
+		b              calendarBuilder
+		inEvent        bool
+		yearly         bool
+		dtstart, dtend string
+		events         int
+	)
+	for _, line := range unfoldICalendarLines(data) {
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+		// Parameters such as `;VALUE=DATE` are not needed.
+		name, value := strings.ToUpper(strings.SplitN(line[:i], ";", 2)[0]), strings.TrimSpace(line[i+1:])
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, yearly, dtstart, dtend = true, false, "", ""
+			events++
+		case !inEvent:
+		case name == "DTSTART":
+			dtstart = value
+		case name == "DTEND":
+			dtend = value
+		case name == "RRULE":
+			yearly = strings.Contains(strings.ToUpper(value), "FREQ=YEARLY")
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			if err := b.addEvent(dtstart, dtend, yearly); err != nil {
+				return nil, fmt.Errorf("VEVENT %d: %v", events, err)
+			}
+		}
+	}
+	return b.calendar(), nil
+}
+
+// addEvent adds the days of a VEVENT, given its DTSTART and DTEND values.
+func (b *calendarBuilder) addEvent(dtstart, dtend string, yearly bool) error {
+	if dtstart == "" {
+		return fmt.Errorf("missing DTSTART")
+	}
+	start, _, err := parseICalendarDate(dtstart)
+	if err != nil {
+		return err
+	}
+	last := start
+	if dtend != "" {
+		end, hasTime, err := parseICalendarDate(dtend)
+		if err != nil {
+			return err
+		}
+		// DTEND is exclusive, so the day it starts is not excluded.
+		if !hasTime || (end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0) {
+			end = end.AddDate(0, 0, -1)
+		}
+		if end.After(start) {
+			last = end
+		}
+	}
+
+	first, lastDate := dateOf(start), dateOf(last)
+	if yearly {
+		first.Year, lastDate.Year = 0, 0
+	}
+	return b.add(first, lastDate)
+}
+
+// parseICalendarDate parses a DATE or DATE-TIME value, such as `20211225` or
+// `20211225T090000Z`, and returns whether it has a time, which is read as UTC.
+func parseICalendarDate(value string) (time.Time, bool, error) {
+	if len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date: %v", err)
+		}
+		return t, false, nil
+	}
+	t, err := time.Parse("20060102T150405", strings.TrimSuffix(value, "Z"))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date: %v", err)
+	}
+	return t, true, nil
+}
+
+// unfoldICalendarLines returns the lines of an iCalendar file, with folded
+// lines, which continue on lines starting with a space or a tab, joined.
+func unfoldICalendarLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,245 @@
+package cronexpr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCalendar(t *testing.T) {
+	from := time.Date(2021, time.December, 22, 12, 0, 0, 0, time.UTC)
+	christmas := mustDateRangeCalendar(t, "12-24", "12-26")
+	tests := []struct {
+		name      string
+		expr      string
+		calendars []Calendar
+		from      time.Time
+		want      []string
+	}{
+		{
+			name:      "fixed dates",
+			expr:      "0 9 * * 1-5",
+			calendars: []Calendar{DateCalendar(Date{2021, time.December, 23}, Date{2021, time.December, 27})},
+			want:      []string{"2021-12-24T09:00:00Z", "2021-12-28T09:00:00Z", "2021-12-29T09:00:00Z"},
+		},
+		{
+			name:      "dates recurring every year",
+			expr:      "0 9 * * 1-5",
+			calendars: []Calendar{DateCalendar(Date{Month: time.December, Day: 24}, Date{Month: time.January, Day: 3})},
+			want:      []string{"2021-12-23T09:00:00Z", "2021-12-27T09:00:00Z", "2021-12-28T09:00:00Z", "2021-12-29T09:00:00Z", "2021-12-30T09:00:00Z", "2021-12-31T09:00:00Z", "2022-01-04T09:00:00Z"},
+		},
+		{
+			name:      "date range",
+			expr:      "0 0 * * *",
+			calendars: []Calendar{christmas},
+			want:      []string{"2021-12-23T00:00:00Z", "2021-12-27T00:00:00Z", "2021-12-28T00:00:00Z"},
+		},
+		{
+			name:      "date range spanning the end of the year",
+			expr:      "0 0 * * *",
+			calendars: []Calendar{mustDateRangeCalendar(t, "12-30", "01-02")},
+			from:      time.Date(2021, time.December, 28, 12, 0, 0, 0, time.UTC),
+			want:      []string{"2021-12-29T00:00:00Z", "2022-01-03T00:00:00Z", "2022-01-04T00:00:00Z"},
+		},
+		{
+			name:      "date range ending on February 29",
+			expr:      "0 0 * * *",
+			calendars: []Calendar{mustDateRangeCalendar(t, "02-27", "02-29")},
+			from:      time.Date(2021, time.February, 25, 12, 0, 0, 0, time.UTC),
+			want:      []string{"2021-02-26T00:00:00Z", "2021-03-01T00:00:00Z", "2021-03-02T00:00:00Z"},
+		},
+		{
+			name:      "cron calendar",
+			expr:      "0 * * * *",
+			calendars: []Calendar{CronCalendar(MustParse("* 0-7,13-23 * * *"))},
+			want:      []string{"2021-12-23T08:00:00Z", "2021-12-23T09:00:00Z", "2021-12-23T10:00:00Z", "2021-12-23T11:00:00Z", "2021-12-23T12:00:00Z", "2021-12-24T08:00:00Z"},
+		},
+		{
+			name:      "several calendars",
+			expr:      "0 0 * * *",
+			calendars: []Calendar{christmas, DateCalendar(Date{2021, time.December, 27}), CronCalendar(MustParse("0 0 * * 2"))},
+			want:      []string{"2021-12-23T00:00:00Z", "2021-12-29T00:00:00Z", "2021-12-30T00:00:00Z"},
+		},
+		{
+			name:      "dates in the time zone of the expression",
+			expr:      "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			calendars: []Calendar{christmas},
+			want:      []string{"2021-12-23T00:00:00Z", "2021-12-27T00:00:00Z", "2021-12-28T00:00:00Z"},
+		},
+		{
+			name:      "dates in the time zone of the given time",
+			expr:      "0 0 * * *",
+			calendars: []Calendar{christmas},
+			from:      time.Date(2021, time.December, 22, 12, 0, 0, 0, mustLoadLocation("America/New_York")),
+			want:      []string{"2021-12-23T05:00:00Z", "2021-12-27T05:00:00Z", "2021-12-28T05:00:00Z"},
+		},
+		{
+			name:      "interval",
+			expr:      "@every 12h",
+			calendars: []Calendar{christmas},
+			want:      []string{"2021-12-23T00:00:00Z", "2021-12-23T12:00:00Z", "2021-12-27T00:00:00Z"},
+		},
+		{
+			name:      "all excluded",
+			expr:      "0 0 25 12 * 2021-2023",
+			calendars: []Calendar{christmas},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.expr, WithCalendar(tt.calendars...))
+			require.NoError(t, err)
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+
+			nexts := expr.NextN(start, uint(len(tt.want)+1))
+			var got []string
+			for _, next := range nexts {
+				got = append(got, next.UTC().Format(time.RFC3339))
+			}
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				assert.True(t, expr.Prev(start.AddDate(3, 0, 0)).Before(start))
+				return
+			}
+			assert.Equal(t, tt.want, got[:len(tt.want)])
+
+			nexts = nexts[:len(tt.want)]
+			last := nexts[len(nexts)-1]
+			prevs := expr.PrevN(last, uint(len(nexts)-1))
+			for i, prev := range prevs {
+				assert.Equal(t, nexts[len(nexts)-2-i], prev)
+			}
+			assert.Equal(t, nexts, expr.Between(start, last, 0))
+
+			// The same calendars exclude the same time instants from a Schedule.
+			assert.Equal(t, nexts[0], ExceptCalendar(MustParse(tt.expr), tt.calendars...).Next(start))
+		})
+	}
+}
+
+func TestWithCalendar_Invalid(t *testing.T) {
+	_, err := Parse("0 0 * * *", WithCalendar(nil))
+	assert.Error(t, err)
+}
+
+func TestDateRangeCalendar_Invalid(t *testing.T) {
+	_, err := DateRangeCalendar(Date{2021, time.December, 24}, Date{Month: time.December, Day: 26})
+	assert.Error(t, err)
+	_, err = DateRangeCalendar(Date{2021, time.December, 26}, Date{2021, time.December, 24})
+	assert.Error(t, err)
+}
+
+func TestParseDate(t *testing.T) {
+	for s, want := range map[string]Date{
+		"2021-12-25": {2021, time.December, 25},
+		"12-25":      {Month: time.December, Day: 25},
+		"02-29":      {Month: time.February, Day: 29},
+	} {
+		d, err := ParseDate(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, d, s)
+		assert.Equal(t, s, d.String())
+	}
+	for _, s := range []string{"", "2021-02-29", "13-01", "12/25", "2021-12-25T00:00:00Z"} {
+		_, err := ParseDate(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestReadCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		excluded []string
+		included []string
+	}{
+		{
+			name: "csv",
+			file: `# Public holidays
+2021-12-24,2021-12-26,Christmas
+12-31,New Year's Eve
+
+01-01
+`,
+			excluded: []string{"2021-12-24", "2021-12-25", "2021-12-26", "2021-12-31", "2022-01-01", "2022-12-31"},
+			included: []string{"2021-12-23", "2021-12-27", "2022-01-02", "2022-12-24"},
+		},
+		{
+			name: "icalendar",
+			file: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"SUMMARY:Christmas\r\n" +
+				"DTSTART;VALUE=DATE:20211224\r\n" +
+				"DTEND;VALUE=DATE:20211227\r\n" +
+				"END:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"SUMMARY:New Year's Day\r\n" +
+				"DTSTART;VALUE=DATE:20220101\r\n" +
+				"RRULE:FREQ=YEARLY\r\n" +
+				"END:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"SUMMARY:Offsite\r\n" +
+				"DTSTART:20220301T090000Z\r\n" +
+				"DTEND:20220302T\r\n" +
+				" 170000Z\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			excluded: []string{"2021-12-24", "2021-12-25", "2021-12-26", "2022-01-01", "2023-01-01", "2022-03-01", "2022-03-02"},
+			included: []string{"2021-12-23", "2021-12-27", "2022-01-02", "2022-03-03", "2023-12-25"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			calendar, err := ReadCalendar(strings.NewReader(tt.file))
+			require.NoError(t, err)
+			for _, s := range tt.excluded {
+				day, err := time.Parse("2006-01-02", s)
+				require.NoError(t, err)
+				start, end, ok := calendar.Exclusion(day.Add(12 * time.Hour))
+				if assert.True(t, ok, "%s is not excluded", s) {
+					assert.False(t, start.After(day), s)
+					assert.False(t, end.Before(day.AddDate(0, 0, 1)), s)
+				}
+			}
+			for _, s := range tt.included {
+				day, err := time.Parse("2006-01-02", s)
+				require.NoError(t, err)
+				_, _, ok := calendar.Exclusion(day.Add(12 * time.Hour))
+				assert.False(t, ok, "%s is excluded", s)
+			}
+		})
+	}
+}
+
+func TestReadCalendar_Invalid(t *testing.T) {
+	for _, file := range []string{
+		"2021-12-32",
+		"christmas,2021-12-25",
+		"2021-12-26,2021-12-24",
+		"12-24,2021-12-26",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:No date\nEND:VEVENT\nEND:VCALENDAR",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2021\nEND:VEVENT\nEND:VCALENDAR",
+	} {
+		_, err := ReadCalendar(strings.NewReader(file))
+		assert.Error(t, err, file)
+	}
+}
+
+func mustDateRangeCalendar(t *testing.T, first, last string) Calendar {
+	firstDate, err := ParseDate(first)
+	require.NoError(t, err)
+	lastDate, err := ParseDate(last)
+	require.NoError(t, err)
+	calendar, err := DateRangeCalendar(firstDate, lastDate)
+	require.NoError(t, err)
+	return calendar
+}
@@ -0,0 +1,237 @@
+package cronexpr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates timers, such that code waiting for the time
+// instants of a Schedule can be tested with a FakeClock rather than by waiting
+// for real time to pass.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer which sends the current time on its channel
+	// once the duration `d` has passed.
+	NewTimer(d time.Duration) Timer
+
+	// After waits for the duration `d` to pass and then sends the current
+	// time on the returned channel, as NewTimer(d).C() does.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a single event created by a Clock, as a time.Timer is.
+type Timer interface {
+	// C returns the channel on which the time is sent when the Timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing, and returns false if it has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+// RealClock is the Clock of the time package.
+type RealClock struct{}
+
+var _ Clock = RealClock{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a Timer wrapping time.NewTimer(d).
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+// After returns time.After(d).
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+/******************************************************************************/
+
+// FakeClock is a Clock whose time only changes when it is advanced. It is safe
+// for concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+var _ Clock = &FakeClock{}
+
+// NewFakeClock returns a FakeClock set to `now`.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the time the FakeClock is set to.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a Timer which fires once the FakeClock is advanced by `d`,
+// or right away if `d` is not positive.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// After returns NewTimer(d).C().
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Advance moves the time forward by `d`, and fires the timers it goes past in
+// the order of their deadlines. Each of them is sent its deadline.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the time forward to `t`, as Advance does. It does nothing if `t`
+// is before the time the FakeClock is set to.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.set(t)
+	}
+}
+
+func (c *FakeClock) set(t time.Time) {
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].deadline.Before(c.timers[j].deadline)
+	})
+	fired := 0
+	for _, timer := range c.timers {
+		if timer.deadline.After(t) {
+			break
+		}
+		timer.c <- timer.deadline
+		fired++
+	}
+	c.timers = c.timers[fired:]
+	c.now = t
+	c.cond.Broadcast()
+}
+
+// Timers returns the deadlines of the timers which have not fired or been
+// stopped, in chronological order.
+func (c *FakeClock) Timers() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deadlines := make([]time.Time, 0, len(c.timers))
+	for _, timer := range c.timers {
+		deadlines = append(deadlines, timer.deadline)
+	}
+	sort.Slice(deadlines, func(i, j int) bool {
+		return deadlines[i].Before(deadlines[j])
+	})
+	return deadlines
+}
+
+// BlockUntil waits until at least `n` timers have not fired or been stopped,
+// e.g. to make sure that the code under test is waiting before advancing.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// BlockUntilTimer waits until a timer is set to fire at `deadline`.
+func (c *FakeClock) BlockUntilTimer(deadline time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for !c.hasTimer(deadline) {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) hasTimer(deadline time.Time) bool {
+	for _, timer := range c.timers {
+		if timer.deadline.Equal(deadline) {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+/******************************************************************************/
+
+// Wait waits on `clock` for the earliest time instant of `schedule` following
+// the current time, and returns it, or returns an error if the schedule has no
+// more time instants or `ctx` is done first.
+func Wait(ctx context.Context, clock Clock, schedule Schedule) (time.Time, error) {
+	now := clock.Now()
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("no time instant following %v", now)
+	}
+	timer := clock.NewTimer(next.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return next, nil
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
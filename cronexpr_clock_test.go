@@ -0,0 +1,86 @@
+package cronexpr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	// Timers fire in the order of their deadlines, with their deadline.
+	fired := make(chan time.Time, 10)
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		c := clock.After(d)
+		go func() {
+			fired <- <-c
+		}()
+	}
+	stopped := clock.NewTimer(2 * time.Second)
+	later := clock.NewTimer(time.Minute)
+	assert.Equal(t, []time.Time{start.Add(time.Second), start.Add(2 * time.Second), start.Add(2 * time.Second), start.Add(3 * time.Second), start.Add(time.Minute)}, clock.Timers())
+
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+	clock.BlockUntil(4)
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, start.Add(5*time.Second), clock.Now())
+	got := []time.Time{<-fired, <-fired, <-fired}
+	assert.ElementsMatch(t, []time.Time{start.Add(time.Second), start.Add(2 * time.Second), start.Add(3 * time.Second)}, got)
+	assert.Equal(t, []time.Time{start.Add(time.Minute)}, clock.Timers())
+	select {
+	case <-stopped.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+
+	// Set does not move the time backward.
+	clock.Set(start)
+	assert.Equal(t, start.Add(5*time.Second), clock.Now())
+	clock.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Minute), <-later.C())
+	assert.False(t, later.Stop())
+
+	// Timers which are already due fire right away.
+	assert.Equal(t, start.Add(time.Hour), <-clock.After(0))
+	assert.Empty(t, clock.Timers())
+}
+
+func TestWait(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 0, 40, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	expr := MustParse("0 * * * *")
+
+	done := make(chan time.Time)
+	go func() {
+		next, err := Wait(context.Background(), clock, expr)
+		assert.NoError(t, err)
+		done <- next
+	}()
+	clock.BlockUntilTimer(time.Date(2021, time.March, 1, 1, 0, 0, 0, time.UTC))
+	clock.Advance(20 * time.Minute)
+	assert.Equal(t, time.Date(2021, time.March, 1, 1, 0, 0, 0, time.UTC), <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Wait(ctx, clock, expr)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, clock.Timers())
+
+	_, err = Wait(context.Background(), clock, MustParse("0 0 * * * 2020"))
+	require.Error(t, err)
+}
+
+func TestRealClock(t *testing.T) {
+	var clock Clock = RealClock{}
+	before := time.Now()
+	assert.False(t, clock.Now().Before(before))
+	<-clock.After(time.Millisecond)
+	timer := clock.NewTimer(time.Hour)
+	assert.True(t, timer.Stop())
+}
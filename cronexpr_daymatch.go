@@ -0,0 +1,41 @@
+package cronexpr
+
+import (
+	"fmt"
+)
+
+// DayMatchMode is an enum for how the day-of-month and day-of-week fields are
+// combined when both of them are restricted.
+type DayMatchMode string
+
+const (
+	// A day matches if either the day-of-month or the day-of-week field matches.
+	// This is the default, as per the crontab man page (http://linux.die.net/man/5/crontab#).
+	DayMatchOr DayMatchMode = "or"
+
+	// A day matches only if both the day-of-month and the day-of-week fields match,
+	// e.g. `0 0 13 * 5` is every Friday the 13th.
+	DayMatchAnd DayMatchMode = "and"
+)
+
+// WithDayMatchMode returns a ParseOption that sets how the day-of-month and
+// day-of-week fields are combined when both of them are restricted.
+// It has no effect if either field is `*` or `?`.
+func WithDayMatchMode(mode DayMatchMode) ParseOption {
+	return &dayMatchParseOption{mode: mode}
+}
+
+type dayMatchParseOption struct {
+	*baseOption
+	mode DayMatchMode
+}
+
+func (o *dayMatchParseOption) Apply(expr *Expression) error {
+	switch o.mode {
+	case DayMatchOr, DayMatchAnd:
+		expr.dayMatchMode = o.mode
+	default:
+		return fmt.Errorf("unknown DayMatchMode: %q", o.mode)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDayMatchMode(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		mode DayMatchMode
+		want []string
+	}{
+		{
+			name: "friday the 13th",
+			expr: "0 0 13 * 5",
+			mode: DayMatchAnd,
+			want: []string{"2021-08-13", "2022-05-13", "2023-01-13", "2023-10-13"},
+		},
+		{
+			name: "13th or friday",
+			expr: "0 0 13 * 5",
+			mode: DayMatchOr,
+			want: []string{"2021-01-01", "2021-01-08", "2021-01-13", "2021-01-15"},
+		},
+		{
+			name: "last day of month on a friday",
+			expr: "0 0 L * 5",
+			mode: DayMatchAnd,
+			want: []string{"2021-04-30", "2021-12-31", "2022-09-30", "2023-03-31"},
+		},
+		{
+			name: "work day nearest the 15th on a monday",
+			expr: "0 0 15W * 1",
+			mode: DayMatchAnd,
+			want: []string{"2021-02-15", "2021-03-15", "2021-08-16", "2021-11-15"},
+		},
+		{
+			name: "last work day of month on the last friday",
+			expr: "0 0 LW * 5L",
+			mode: DayMatchAnd,
+			want: []string{"2021-01-29", "2021-02-26", "2021-04-30", "2021-07-30"},
+		},
+		{
+			name: "last work day of month or the last friday",
+			expr: "0 0 LW * 5L",
+			mode: DayMatchOr,
+			want: []string{"2021-01-29", "2021-02-26", "2021-03-26", "2021-03-31"},
+		},
+		{
+			name: "first monday of month",
+			expr: "0 0 1-7 * 1",
+			mode: DayMatchAnd,
+			want: []string{"2021-01-04", "2021-02-01", "2021-03-01", "2021-04-05"},
+		},
+		{
+			name: "day before last day of month on the fifth saturday",
+			expr: "0 0 L-1 * 6#5",
+			mode: DayMatchAnd,
+			want: []string{"2021-01-30", "2021-10-30", "2022-07-30", "2023-04-29"},
+		},
+		{
+			name: "unrestricted day of week",
+			expr: "0 0 13 * *",
+			mode: DayMatchAnd,
+			want: []string{"2021-01-13", "2021-02-13", "2021-03-13", "2021-04-13"},
+		},
+		{
+			name: "unrestricted day of month",
+			expr: "0 0 * * 5",
+			mode: DayMatchAnd,
+			want: []string{"2021-01-01", "2021-01-08", "2021-01-15", "2021-01-22"},
+		},
+		{
+			name: "never matching",
+			expr: "0 0 8-14 * 2#3 2021-2030",
+			mode: DayMatchAnd,
+		},
+	}
+	from := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.expr, WithDayMatchMode(tt.mode))
+			require.NoError(t, err)
+			nexts := expr.NextN(from.Add(-time.Second), 4)
+			var got []string
+			for _, next := range nexts {
+				got = append(got, next.Format("2006-01-02"))
+			}
+			assert.Equal(t, tt.want, got)
+
+			// Between and Prev agree with Next.
+			if len(nexts) > 0 {
+				last := nexts[len(nexts)-1]
+				assert.Equal(t, nexts, expr.Between(from.Add(-time.Second), last, 0))
+				assert.Equal(t, nexts[len(nexts)-2], expr.Prev(last))
+			}
+		})
+	}
+}
+
+func TestWithDayMatchMode_Describe(t *testing.T) {
+	assert.Equal(t, "At 00:00 on day 13 of every month if also on Friday",
+		MustParse("0 0 13 * 5", WithDayMatchMode(DayMatchAnd)).Describe())
+	assert.Equal(t, "At 00:00 on the last weekday of March if also on the last Friday of March",
+		MustParse("0 0 LW 3 5L", WithDayMatchMode(DayMatchAnd)).Describe())
+	assert.Equal(t, "At 00:00 on day 13 of every month",
+		MustParse("0 0 13 * *", WithDayMatchMode(DayMatchAnd)).Describe())
+}
+
+func TestWithDayMatchMode_Invalid(t *testing.T) {
+	_, err := Parse("0 0 13 * 5", WithDayMatchMode("xor"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,457 @@
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Locale holds the words and templates used to describe an Expression in a
+// given language. Templates are format strings for fmt.Sprintf, and may use
+// explicit argument indexes (e.g. `%[2]s`) where a language needs a different
+// word order.
+//
+// To support another language, define a new Locale and pass it to DescribeLocale.
+type Locale struct {
+	// Names of the days of the week, starting from Sunday.
+	Weekdays [7]string
+
+	// Names of the months, starting from January.
+	Months [12]string
+
+	// Ordinal words used for `#`, starting from "first".
+	Ordinals [5]string
+
+	// OrdinalNumber formats a number as an ordinal, e.g. "3rd", for
+	// EveryNthDayOfMonth. The DayOfMonth templates are used instead if it is nil.
+	OrdinalNumber func(n int) string
+
+	// ListSeparator separates items of a list, except for the last two items
+	// which are separated by ListLastSeparator.
+	ListSeparator     string
+	ListLastSeparator string
+
+	// RangeItem describes a range within a list of values, e.g. "%s through %s".
+	RangeItem string
+
+	// Or separates alternative days when both day of month and day of week are restricted.
+	Or string
+
+	// And separates the days of month from the days of week they must also
+	// fall on, when both are restricted and combined with DayMatchAnd.
+	And string
+
+	// ClauseSeparator separates the clauses of the time of day.
+	ClauseSeparator string
+
+	// AtTimes describes a list of times of day, e.g. "at %s".
+	AtTimes string
+
+	// TimeOfDay and TimeOfDayWithSeconds format a time of day from its hour, minute and second.
+	TimeOfDay            string
+	TimeOfDayWithSeconds string
+
+	// StartOfHour describes minute 0 when the hours are not described as times
+	// of day, e.g. "at the start of the hour".
+	StartOfHour string
+
+	// Templates for the fields of an Expression.
+	Second, Minute, Hour, DayOfMonth, DayOfWeek, Month, Year FieldLocale
+
+	// HourValue formats a single hour within the templates of Hour.
+	HourValue string
+
+	// Templates for special days of the month.
+	EveryNthDayOfMonth           string
+	LastDayOfMonth               string
+	DayBeforeLastDayOfMonth      string
+	DaysBeforeLastDayOfMonth     string
+	LastWorkdayOfMonth           string
+	WorkdayBeforeLastDayOfMonth  string
+	WorkdaysBeforeLastDayOfMonth string
+	WorkdayOfMonth               string
+	LastDayOfWeek                string
+	SpecificDayOfWeek            string
+
+	// OfEveryMonth and OfMonths complete a day which is relative to the month,
+	// depending on whether the month is restricted.
+	OfEveryMonth string
+	OfMonths     string
+
+	// Every describes an `@every` interval schedule, e.g. "every %s", and
+	// EveryWithPhase one whose phase is not zero, e.g. "every %s, offset by %s".
+	Every          string
+	EveryWithPhase string
+
+	// InLocation describes the time zone of an Expression which has one, e.g. "in %s time".
+	InLocation string
+}
+
+// FieldLocale holds the templates used to describe the values of a single field.
+type FieldLocale struct {
+	// Every describes all values of the field, e.g. "every minute".
+	Every string
+
+	// EveryN describes an interval over all values of the field, e.g. "every %d minutes".
+	EveryN string
+
+	// EveryNRange describes an interval over a range, e.g. "every %d minutes from minute %s through %s".
+	EveryNRange string
+
+	// One describes a single value, e.g. "at minute %s".
+	One string
+
+	// Many describes a list of values, e.g. "at minutes %s".
+	Many string
+
+	// Range describes a range of values, e.g. "minutes %s through %s".
+	Range string
+}
+
+// LocaleEnglish is the default Locale used by Describe.
+var LocaleEnglish = &Locale{
+	Weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	Months: [12]string{"January", "February", "March", "April", "May", "June", "July",
+		"August", "September", "October", "November", "December"},
+	Ordinals:             [5]string{"first", "second", "third", "fourth", "fifth"},
+	OrdinalNumber:        englishOrdinalNumber,
+	ListSeparator:        ", ",
+	ListLastSeparator:    " and ",
+	RangeItem:            "%s through %s",
+	Or:                   " or ",
+	And:                  " if also ",
+	ClauseSeparator:      ", ",
+	AtTimes:              "at %s",
+	TimeOfDay:            "%02d:%02d",
+	TimeOfDayWithSeconds: "%02d:%02d:%02d",
+	StartOfHour:          "at the start of the hour",
+	Second: FieldLocale{
+		Every:       "every second",
+		EveryN:      "every %d seconds",
+		EveryNRange: "every %d seconds from second %s through %s",
+		One:         "at second %s",
+		Many:        "at seconds %s",
+		Range:       "seconds %s through %s",
+	},
+	Minute: FieldLocale{
+		Every:       "every minute",
+		EveryN:      "every %d minutes",
+		EveryNRange: "every %d minutes from minute %s through %s past the hour",
+		One:         "at %s minutes past the hour",
+		Many:        "at %s minutes past the hour",
+		Range:       "minutes %s through %s past the hour",
+	},
+	Hour: FieldLocale{
+		Every:       "every hour",
+		EveryN:      "every %d hours",
+		EveryNRange: "every %d hours between %s:00 and %s:59",
+		One:         "between %[1]s:00 and %[1]s:59",
+		Many:        "during hours %s",
+		Range:       "between %s:00 and %s:59",
+	},
+	HourValue: "%02d",
+	DayOfMonth: FieldLocale{
+		EveryN:      "every %d days",
+		EveryNRange: "every %d days from day %s through %s",
+		One:         "on day %s",
+		Many:        "on days %s",
+		Range:       "on days %s through %s",
+	},
+	DayOfWeek: FieldLocale{
+		One:   "on %s",
+		Many:  "on %s",
+		Range: "on %s through %s",
+	},
+	Month: FieldLocale{
+		EveryN:      "every %d months",
+		EveryNRange: "every %d months from %s through %s",
+		One:         "in %s",
+		Many:        "in %s",
+		Range:       "in %s through %s",
+	},
+	Year: FieldLocale{
+		EveryN:      "every %d years",
+		EveryNRange: "every %d years from %s through %s",
+		One:         "in %s",
+		Many:        "in %s",
+		Range:       "in %s through %s",
+	},
+	EveryNthDayOfMonth:           "every %s day",
+	LastDayOfMonth:               "on the last day",
+	DayBeforeLastDayOfMonth:      "on the day before the last day",
+	DaysBeforeLastDayOfMonth:     "on the day %s days before the last day",
+	LastWorkdayOfMonth:           "on the last weekday",
+	WorkdayBeforeLastDayOfMonth:  "on the weekday nearest the day before the last day",
+	WorkdaysBeforeLastDayOfMonth: "on the weekday nearest %s days before the last day",
+	WorkdayOfMonth:               "on the weekday nearest day %s",
+	LastDayOfWeek:                "on the last %s",
+	SpecificDayOfWeek:            "on the %s %s",
+	OfEveryMonth:                 "of every month",
+	OfMonths:                     "of %s",
+	Every:                        "every %s",
+	EveryWithPhase:               "every %s, offset by %s",
+	InLocation:                   "in %s time",
+}
+
+// Describe returns a human-readable English description of the Expression,
+// such as "At 03:15 on the last Friday of every month".
+func (expr *Expression) Describe() string {
+	return expr.DescribeLocale(LocaleEnglish)
+}
+
+// DescribeLocale returns a human-readable description of the Expression using the given Locale.
+func (expr *Expression) DescribeLocale(l *Locale) string {
+	if expr.every != 0 {
+		every := fmt.Sprintf(l.Every, expr.every)
+		if expr.everyPhase != 0 {
+			every = fmt.Sprintf(l.EveryWithPhase, expr.every, expr.everyPhase)
+		}
+		return capitalize(every + expr.describeLocation(l))
+	}
+
+	description := expr.describeTimeOfDay(l)
+
+	days, monthRelative := expr.describeDays(l)
+	if days != "" {
+		description += " " + days
+	}
+	if !monthRelative {
+		if months := l.describeField(l.Month, expr.months.list(), monthDescriptor, l.monthName); months != "" {
+			description += l.ClauseSeparator + months
+		}
+	}
+	if years := l.describeField(l.Year, expr.yearList, yearDescriptor, strconv.Itoa); years != "" {
+		description += l.ClauseSeparator + years
+	}
+
+	return capitalize(description + expr.describeLocation(l))
+}
+
+// describeLocation returns the description of the time zone of the
+// Expression as a separate clause, or an empty string if it has none.
+func (expr *Expression) describeLocation(l *Locale) string {
+	if expr.location == nil {
+		return ""
+	}
+	return l.ClauseSeparator + fmt.Sprintf(l.InLocation, expr.location)
+}
+
+func (expr *Expression) describeTimeOfDay(l *Locale) string {
+	seconds, minutes, hours := expr.seconds.list(), expr.minutes.list(), expr.hours.list()
+	if len(seconds) == 1 && len(minutes) == 1 && allSingleValues(listSegments(hours)) {
+		times := make([]string, len(hours))
+		for i, hour := range hours {
+			if seconds[0] == 0 {
+				times[i] = fmt.Sprintf(l.TimeOfDay, hour, minutes[0])
+			} else {
+				times[i] = fmt.Sprintf(l.TimeOfDayWithSeconds, hour, minutes[0], seconds[0])
+			}
+		}
+		return fmt.Sprintf(l.AtTimes, l.join(times))
+	}
+
+	// Describe each field separately, from seconds to hours. A field having all
+	// values is only mentioned if no finer field was.
+	var clauses []string
+	if !(len(seconds) == 1 && seconds[0] == 0) {
+		clauses = append(clauses, l.describeField(l.Second, seconds, secondDescriptor, strconv.Itoa))
+	}
+	if len(minutes) == 1 && minutes[0] == 0 {
+		clauses = append(clauses, l.StartOfHour)
+	} else if len(minutes) != len(minuteDescriptor.defaultList) || len(clauses) == 0 {
+		clauses = append(clauses, l.describeField(l.Minute, minutes, minuteDescriptor, strconv.Itoa))
+	}
+	if len(hours) != len(hourDescriptor.defaultList) {
+		clauses = append(clauses, l.describeField(l.Hour, hours, hourDescriptor, l.hourValue))
+	}
+	return strings.Join(clauses, l.ClauseSeparator)
+}
+
+// describeDays returns the description of the days of month and days of week, and
+// whether the months were described along with days relative to the month.
+func (expr *Expression) describeDays(l *Locale) (string, bool) {
+	var monthDays []string
+
+	if expr.daysOfMonthRestricted {
+		if expr.daysOfMonth != 0 {
+			monthDays = append(monthDays, l.describeDaysOfMonth(expr.daysOfMonth.list()))
+		}
+		for _, v := range expr.workdaysOfMonth.list() {
+			monthDays = append(monthDays, fmt.Sprintf(l.WorkdayOfMonth, strconv.Itoa(v)))
+		}
+		if expr.lastDayOfMonth {
+			monthDays = append(monthDays, l.LastDayOfMonth)
+		}
+		for _, v := range expr.daysBeforeLastDayOfMonth.list() {
+			if v == 1 {
+				monthDays = append(monthDays, l.DayBeforeLastDayOfMonth)
+			} else {
+				monthDays = append(monthDays, fmt.Sprintf(l.DaysBeforeLastDayOfMonth, strconv.Itoa(v)))
+			}
+		}
+		if expr.lastWorkdayOfMonth {
+			monthDays = append(monthDays, l.LastWorkdayOfMonth)
+		}
+		for _, v := range expr.workdaysBeforeLastDayOfMonth.list() {
+			if v == 1 {
+				monthDays = append(monthDays, l.WorkdayBeforeLastDayOfMonth)
+			} else {
+				monthDays = append(monthDays, fmt.Sprintf(l.WorkdaysBeforeLastDayOfMonth, strconv.Itoa(v)))
+			}
+		}
+	}
+
+	var weekMonthDays []string
+	var weekDays string
+	if expr.daysOfWeekRestricted {
+		for _, v := range expr.lastWeekDaysOfWeek.list() {
+			weekMonthDays = append(weekMonthDays, fmt.Sprintf(l.LastDayOfWeek, l.weekdayName(v)))
+		}
+		for _, v := range expr.specificWeekDaysOfWeek.list() {
+			weekMonthDays = append(weekMonthDays, fmt.Sprintf(l.SpecificDayOfWeek, l.Ordinals[v/7], l.weekdayName(v%7)))
+		}
+		if expr.daysOfWeek != 0 {
+			list := expr.daysOfWeek.list()
+			switch segments := listSegments(list); {
+			case len(list) == 1:
+				weekDays = fmt.Sprintf(l.DayOfWeek.One, l.weekdayName(list[0]))
+			case len(segments) == 1 && segments[0].step == 1:
+				weekDays = fmt.Sprintf(l.DayOfWeek.Range, l.weekdayName(list[0]), l.weekdayName(list[len(list)-1]))
+			default:
+				weekDays = fmt.Sprintf(l.DayOfWeek.Many, l.list(list, l.weekdayName))
+			}
+		}
+	}
+
+	// With DayMatchAnd, the days of week further restrict the days of month.
+	if expr.daysOfMonthRestricted && expr.daysOfWeekRestricted && expr.dayMatchMode == DayMatchAnd {
+		return expr.joinDays(l, monthDays, "") + l.And + expr.joinDays(l, weekMonthDays, weekDays), true
+	}
+
+	monthDays = append(monthDays, weekMonthDays...)
+	return expr.joinDays(l, monthDays, weekDays), len(monthDays) > 0
+}
+
+// joinDays returns the description of alternative days, which are either
+// relative to the month or plain days of week.
+func (expr *Expression) joinDays(l *Locale, monthDays []string, weekDays string) string {
+	var days []string
+
+	// Days relative to the month are followed by the months they apply to.
+	if len(monthDays) > 0 {
+		of := l.OfEveryMonth
+		if months := expr.months.list(); len(months) != len(monthDescriptor.defaultList) {
+			of = fmt.Sprintf(l.OfMonths, l.list(months, l.monthName))
+		}
+		days = append(days, strings.Join(monthDays, l.Or)+" "+of)
+	}
+	if weekDays != "" {
+		days = append(days, weekDays)
+	}
+	return strings.Join(days, l.Or)
+}
+
+// describeField describes a sorted list of values using the templates of a field.
+// An empty string is returned if the list has all values of the field, and the
+// field has no template for it.
+func (l *Locale) describeField(fl FieldLocale, list []int, desc fieldDescriptor, name func(int) string) string {
+	if list == nil || len(list) == desc.max-desc.min+1 {
+		return fl.Every
+	}
+
+	segments := listSegments(list)
+	if len(segments) == 1 {
+		seg := segments[0]
+		switch {
+		case seg.first == seg.last:
+			return fmt.Sprintf(fl.One, name(seg.first))
+		case seg.step == 1:
+			return fmt.Sprintf(fl.Range, name(seg.first), name(seg.last))
+		case seg.first == desc.min && seg.last+seg.step > desc.max && fl.EveryN != "":
+			return fmt.Sprintf(fl.EveryN, seg.step)
+		case fl.EveryNRange != "":
+			return fmt.Sprintf(fl.EveryNRange, seg.step, name(seg.first), name(seg.last))
+		}
+	}
+	return fmt.Sprintf(fl.Many, l.list(list, name))
+}
+
+// list returns a human-readable list of values, using ranges where possible.
+func (l *Locale) list(list []int, name func(int) string) string {
+	var items []string
+	for _, seg := range listSegments(list) {
+		switch {
+		case seg.first == seg.last:
+			items = append(items, name(seg.first))
+		case seg.step == 1:
+			items = append(items, fmt.Sprintf(l.RangeItem, name(seg.first), name(seg.last)))
+		default:
+			for v := seg.first; v <= seg.last; v += seg.step {
+				items = append(items, name(v))
+			}
+		}
+	}
+	return l.join(items)
+}
+
+func (l *Locale) join(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], l.ListSeparator) + l.ListLastSeparator + items[len(items)-1]
+}
+
+// describeDaysOfMonth describes days of month given as numbers, such as
+// "every 3rd day" for `*/3`.
+func (l *Locale) describeDaysOfMonth(list []int) string {
+	if segments := listSegments(list); len(segments) == 1 && l.OrdinalNumber != nil && l.EveryNthDayOfMonth != "" {
+		seg := segments[0]
+		if seg.step > 1 && seg.first == domDescriptor.min && seg.last+seg.step > domDescriptor.max {
+			return fmt.Sprintf(l.EveryNthDayOfMonth, l.OrdinalNumber(seg.step))
+		}
+	}
+	return l.describeField(l.DayOfMonth, list, domDescriptor, strconv.Itoa)
+}
+
+// englishOrdinalNumber returns `n` followed by its English ordinal suffix, e.g. "2nd".
+func englishOrdinalNumber(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+func (l *Locale) weekdayName(v int) string {
+	return l.Weekdays[v%7]
+}
+
+func (l *Locale) monthName(v int) string {
+	return l.Months[v-1]
+}
+
+func (l *Locale) hourValue(v int) string {
+	return fmt.Sprintf(l.HourValue, v)
+}
+
+func allSingleValues(segments []segment) bool {
+	for _, seg := range segments {
+		if seg.first != seg.last {
+			return false
+		}
+	}
+	return true
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
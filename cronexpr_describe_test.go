@@ -0,0 +1,126 @@
+package cronexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Describe(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		want    string
+	}{
+		{name: "every minute", expr: "* * * * *", want: "Every minute"},
+		{name: "every second", expr: "* * * * * * *", want: "Every second"},
+		{name: "minutes interval", expr: "*/5 * * * *", want: "Every 5 minutes"},
+		{name: "minutes range interval", expr: "10-40/10 * * * *", want: "Every 10 minutes from minute 10 through 40 past the hour"},
+		{name: "single minute", expr: "15 * * * *", want: "At 15 minutes past the hour"},
+		{name: "second", expr: "30 * * * * * *", want: "At second 30"},
+		{name: "hours interval", expr: "0 */2 * * *", want: "At the start of the hour, every 2 hours"},
+		{name: "time of day", expr: "15 3 * * *", want: "At 03:15"},
+		{name: "time of day with seconds", expr: "30 0 0 1 1 * *", want: "At 00:00:30 on day 1 of January"},
+		{name: "times of day", expr: "0 0,12 * * *", want: "At 00:00 and 12:00"},
+		{name: "hours range", expr: "0 9-17 * * *", want: "At the start of the hour, between 09:00 and 17:59"},
+		{name: "week days", expr: "0 9-17 * * 1-5", want: "At the start of the hour, between 09:00 and 17:59 on Monday through Friday"},
+		{name: "list of week days", expr: "0 0 * * */2", want: "At 00:00 on Sunday, Tuesday, Thursday and Saturday"},
+		{name: "week day in month", expr: "0 0 * jan 1", want: "At 00:00 on Monday, in January"},
+		{name: "days of month", expr: "0 0 1-10 * *", want: "At 00:00 on days 1 through 10 of every month"},
+		{name: "days of month interval", expr: "0 0 */3 * *", want: "At 00:00 every 3rd day of every month"},
+		{name: "days of month interval in months", expr: "0 0 */2 1,7 *", want: "At 00:00 every 2nd day of January and July"},
+		{name: "days of month range interval", expr: "0 0 1-15/3 * *", want: "At 00:00 every 3 days from day 1 through 13 of every month"},
+		{name: "day of month or week", expr: "0 0 13 * 5", want: "At 00:00 on day 13 of every month or on Friday"},
+		{name: "last day of month", expr: "0 0 L * *", want: "At 00:00 on the last day of every month"},
+		{name: "last work day of month", expr: "0 0 LW * *", want: "At 00:00 on the last weekday of every month"},
+		{
+			name: "work day of month",
+			expr: "0 0 15W 3/3 *",
+			want: "At 00:00 on the weekday nearest day 15 of March, June, September and December",
+		},
+		{name: "day before last day of month", expr: "0 0 L-1 * *", want: "At 00:00 on the day before the last day of every month"},
+		{name: "days before last day of month", expr: "0 17 L-2 * *", want: "At 17:00 on the day 2 days before the last day of every month"},
+		{name: "days before last day of month at midnight", expr: "0 0 L-3 * *", want: "At 00:00 on the day 3 days before the last day of every month"},
+		{name: "work day before last day of month", expr: "0 17 L-1W * *", want: "At 17:00 on the weekday nearest the day before the last day of every month"},
+		{name: "work days before last day of month", expr: "0 17 L-2W 3,6 *", want: "At 17:00 on the weekday nearest 2 days before the last day of March and June"},
+		{name: "last day of week", expr: "15 3 * * 5L", want: "At 03:15 on the last Friday of every month"},
+		{name: "specific day of week", expr: "0 0 * jan,jul 2#3", want: "At 00:00 on the third Tuesday of January and July"},
+		{name: "year", expr: "0 0 * * * 2021", want: "At 00:00, in 2021"},
+		{name: "years", expr: "0 0 0 1 1 * 2000-2010", want: "At 00:00 on day 1 of January, in 2000 through 2010"},
+		{
+			name:    "hash",
+			expr:    "H H(0-7) * * *",
+			options: []ParseOption{WithHash("myid1")},
+			want:    "At 03:59",
+		},
+		{name: "day of week", expr: "0 0 * * 2", want: "At 00:00 on Tuesday"},
+		{
+			name:   "day of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 2 *",
+			want:   "At 11:00 on Monday",
+		},
+		{
+			name:   "last day of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 15 10 ? * 6L *",
+			want:   "At 10:15 on the last Friday of every month",
+		},
+		{
+			name:   "days of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 2-6 *",
+			want:   "At 11:00 on Monday through Friday",
+		},
+		{name: "interval", expr: "@every 90m", want: "Every 1h30m0s"},
+		{name: "interval with phase", expr: "@every 1h 15m", want: "Every 1h0m0s, offset by 15m0s"},
+		{
+			name:   "time zone with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "CRON_TZ=Asia/Tokyo 0 9 * * 1-5",
+			want:   "At 09:00 on Monday through Friday, in Asia/Tokyo time",
+		},
+		{
+			name:   "interval with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "@every 1h30m",
+			want:   "Every 1h30m0s",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			expr, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.Describe())
+		})
+	}
+}
+
+func TestExpression_DescribeLocale(t *testing.T) {
+	locale := *LocaleEnglish
+	locale.Weekdays = [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+	locale.Ordinals = [5]string{"premier", "deuxième", "troisième", "quatrième", "cinquième"}
+	locale.AtTimes = "à %s"
+	locale.ListLastSeparator = " et "
+	locale.TimeOfDay = "%02dh%02d"
+	locale.SpecificDayOfWeek = "le %s %s"
+	locale.OfEveryMonth = "de chaque mois"
+
+	expr := MustParse("0 8,18 * * 1#2")
+	assert.Equal(t, "À 08h00 et 18h00 le deuxième lundi de chaque mois", expr.DescribeLocale(&locale))
+}
+
+func TestEnglishOrdinalNumber(t *testing.T) {
+	tests := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 23: "23rd", 30: "30th"}
+	for n, want := range tests {
+		assert.Equal(t, want, englishOrdinalNumber(n))
+	}
+}
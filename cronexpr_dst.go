@@ -0,0 +1,216 @@
+package cronexpr
+
+import (
+	"fmt"
+	"time"
+)
+
+// DSTPolicy sets how wall clock times which are skipped or repeated due to a
+// daylight saving time change are handled. The zero value is the default
+// policy, that is, SpringForwardSkip and FallBackBoth.
+type DSTPolicy struct {
+	// SpringForward applies to wall clock times which do not exist, as the
+	// clock moves forward over them.
+	SpringForward SpringForwardPolicy `json:"springForward,omitempty"`
+
+	// FallBack applies to wall clock times which happen twice, as the clock
+	// moves back over them.
+	FallBack FallBackPolicy `json:"fallBack,omitempty"`
+}
+
+// SpringForwardPolicy is an enum for how matching wall clock times which are
+// skipped by a daylight saving time change are handled.
+type SpringForwardPolicy string
+
+const (
+	// Skipped wall clock times do not match. This is the default, e.g. `30 2 * * *`
+	// does not run on the day that 2:00 moves forward to 3:00.
+	SpringForwardSkip SpringForwardPolicy = "skip"
+
+	// Skipped wall clock times match the first time instant after the change,
+	// once however many of them are skipped, e.g. `30 2 * * *` runs at 3:00 on
+	// the day that 2:00 moves forward to 3:00.
+	SpringForwardNextValid SpringForwardPolicy = "next-valid"
+)
+
+// FallBackPolicy is an enum for how matching wall clock times which are
+// repeated by a daylight saving time change are handled.
+type FallBackPolicy string
+
+const (
+	// Repeated wall clock times match both of their time instants. This is the
+	// default, e.g. `30 1 * * *` runs twice on the day that 2:00 moves back to 1:00.
+	FallBackBoth FallBackPolicy = "both"
+
+	// Repeated wall clock times match their first time instant only, that is,
+	// the one before the change.
+	FallBackFirst FallBackPolicy = "first"
+
+	// Repeated wall clock times match their last time instant only, that is,
+	// the one after the change.
+	FallBackLast FallBackPolicy = "last"
+)
+
+// WithDSTPolicy returns a ParseOption that sets how wall clock times which are
+// skipped or repeated due to a daylight saving time change are handled.
+// Empty fields of `policy` keep their default.
+func WithDSTPolicy(policy DSTPolicy) ParseOption {
+	return &dstPolicyParseOption{policy: policy}
+}
+
+type dstPolicyParseOption struct {
+	*baseOption
+	policy DSTPolicy
+}
+
+func (o *dstPolicyParseOption) Apply(expr *Expression) error {
+	switch o.policy.SpringForward {
+	case "", SpringForwardSkip, SpringForwardNextValid:
+	default:
+		return fmt.Errorf("unknown SpringForwardPolicy: %q", o.policy.SpringForward)
+	}
+	switch o.policy.FallBack {
+	case "", FallBackBoth, FallBackFirst, FallBackLast:
+	default:
+		return fmt.Errorf("unknown FallBackPolicy: %q", o.policy.FallBack)
+	}
+	expr.dstPolicy = o.policy.normalized()
+	return nil
+}
+
+// normalized returns the policy with its default fields left empty, such that
+// equivalent policies compare equal.
+func (p DSTPolicy) normalized() DSTPolicy {
+	if p.SpringForward == SpringForwardSkip {
+		p.SpringForward = ""
+	}
+	if p.FallBack == FallBackBoth {
+		p.FallBack = ""
+	}
+	return p
+}
+
+// DSTPolicy returns the policy for wall clock times which are skipped or
+// repeated due to a daylight saving time change, with default fields left empty.
+func (expr *Expression) DSTPolicy() DSTPolicy {
+	return expr.dstPolicy
+}
+
+/******************************************************************************/
+
+// nextWithDSTPolicy returns next, adjusted for the DSTPolicy.
+func (expr *Expression) nextWithDSTPolicy(fromTime time.Time) time.Time {
+	t := expr.next(fromTime)
+	if expr.dstPolicy == (DSTPolicy{}) {
+		return t
+	}
+
+	for !t.IsZero() && expr.skipsRepeated(t) {
+		t = expr.next(t)
+	}
+
+	if expr.dstPolicy.SpringForward == SpringForwardNextValid {
+		// The earliest matching wall clock time following that of `fromTime`
+		// may have been skipped, in which case the end of the skipped span
+		// matches instead.
+		wall := expr.next(wallClock(fromTime))
+		if end, ok := skippedSpanEnd(wall, fromTime.Location()); ok && end.After(fromTime) && (t.IsZero() || end.Before(t)) {
+			t = end
+		}
+	}
+	return t
+}
+
+// prevWithDSTPolicy returns prev, adjusted for the DSTPolicy.
+func (expr *Expression) prevWithDSTPolicy(fromTime time.Time) time.Time {
+	t := expr.prev(fromTime)
+	if expr.dstPolicy == (DSTPolicy{}) {
+		return t
+	}
+
+	for !t.IsZero() && expr.skipsRepeated(t) {
+		t = expr.prev(t)
+	}
+
+	if expr.dstPolicy.SpringForward == SpringForwardNextValid {
+		wall := expr.prev(wallClock(fromTime))
+		if end, ok := skippedSpanEnd(wall, fromTime.Location()); ok && end.Before(fromTime) && end.After(t) {
+			t = end
+		}
+	}
+	return t
+}
+
+// skipsRepeated returns whether `t` is the time instant of a repeated wall
+// clock time which does not match as per the FallBackPolicy.
+func (expr *Expression) skipsRepeated(t time.Time) bool {
+	if expr.dstPolicy.FallBack == "" {
+		return false
+	}
+	other, ok := otherOccurrence(t)
+	if !ok {
+		return false
+	}
+	if expr.dstPolicy.FallBack == FallBackFirst {
+		return other.Before(t)
+	}
+	return other.After(t)
+}
+
+// otherOccurrence returns the other time instant having the same wall clock
+// time as `t`, if the wall clock time is repeated due to a daylight saving
+// time change. As in timeZoneInDay, there is at most one change within a day.
+func otherOccurrence(t time.Time) (time.Time, bool) {
+	_, off := t.Zone()
+	for _, u := range []time.Time{t.AddDate(0, 0, -1), t.AddDate(0, 0, 1)} {
+		_, otherOff := u.Zone()
+		if otherOff == off {
+			continue
+		}
+		other := t.Add(time.Duration(off-otherOff) * time.Second)
+		if _, o := other.Zone(); o == otherOff {
+			return other, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// wallClock returns the wall clock time of `t` in UTC, where there are no
+// daylight saving time changes.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// skippedSpanEnd returns the first time instant in `loc` after the span of
+// wall clock times skipped by a daylight saving time change, if the wall
+// clock time `wall` (in UTC, see wallClock) is one of them.
+func skippedSpanEnd(wall time.Time, loc *time.Location) (time.Time, bool) {
+	if wall.IsZero() {
+		return time.Time{}, false
+	}
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	if wallClock(t).Equal(wall) {
+		return time.Time{}, false
+	}
+
+	// The change is between `wall` read with the offset after it and `wall`
+	// read with the offset before it.
+	_, before := t.AddDate(0, 0, -1).Zone()
+	_, after := t.AddDate(0, 0, 1).Zone()
+	if after <= before {
+		return t, true
+	}
+	lo := time.Unix(wall.Unix()-int64(after), 0).In(loc)
+	hi := time.Unix(wall.Unix()-int64(before), 0).In(loc)
+
+	// Search for the first second having the offset after the change.
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if _, midOff := mid.Zone(); midOff == after {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, true
+}
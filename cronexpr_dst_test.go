@@ -0,0 +1,238 @@
+package cronexpr
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	nextValid = DSTPolicy{SpringForward: SpringForwardNextValid}
+	fallFirst = DSTPolicy{FallBack: FallBackFirst}
+	fallLast  = DSTPolicy{FallBack: FallBackLast}
+)
+
+func TestWithDSTPolicy(t *testing.T) {
+	losAngeles := mustLoadLocation("America/Los_Angeles")
+	lordHowe := mustLoadLocation("Australia/Lord_Howe")
+	saoPaulo := mustLoadLocation("America/Sao_Paulo")
+
+	tests := []struct {
+		name   string
+		expr   string
+		policy DSTPolicy
+		from   time.Time
+		want   []string
+	}{
+		// Los Angeles moves from 2:00 to 3:00 on March 10, 2019.
+		{
+			name: "spring forward: skip",
+			expr: "30 2 * * *",
+			from: time.Date(2019, time.March, 9, 0, 0, 0, 0, losAngeles),
+			want: []string{"2019-03-09T02:30:00-08:00", "2019-03-11T02:30:00-07:00", "2019-03-12T02:30:00-07:00"},
+		},
+		{
+			name:   "spring forward: next valid",
+			expr:   "30 2 * * *",
+			policy: nextValid,
+			from:   time.Date(2019, time.March, 9, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-03-09T02:30:00-08:00", "2019-03-10T03:00:00-07:00", "2019-03-11T02:30:00-07:00"},
+		},
+		{
+			name:   "spring forward: next valid once for many skipped times",
+			expr:   "*/20 * * * *",
+			policy: nextValid,
+			from:   time.Date(2019, time.March, 10, 1, 30, 0, 0, losAngeles),
+			want:   []string{"2019-03-10T01:40:00-08:00", "2019-03-10T03:00:00-07:00", "2019-03-10T03:20:00-07:00"},
+		},
+		{
+			name:   "spring forward: next valid with a time zone prefix",
+			expr:   "CRON_TZ=America/Los_Angeles 30 2 * * *",
+			policy: nextValid,
+			from:   time.Date(2019, time.March, 9, 0, 0, 0, 0, time.UTC),
+			want:   []string{"2019-03-09T10:30:00Z", "2019-03-10T10:00:00Z", "2019-03-11T09:30:00Z"},
+		},
+
+		// Los Angeles moves from 2:00 back to 1:00 on November 3, 2019.
+		{
+			name: "fall back: both",
+			expr: "30 1 * * *",
+			from: time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want: []string{"2019-11-03T01:30:00-07:00", "2019-11-03T01:30:00-08:00", "2019-11-04T01:30:00-08:00"},
+		},
+		{
+			name:   "fall back: first",
+			expr:   "30 1 * * *",
+			policy: fallFirst,
+			from:   time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-11-03T01:30:00-07:00", "2019-11-04T01:30:00-08:00", "2019-11-05T01:30:00-08:00"},
+		},
+		{
+			name:   "fall back: last",
+			expr:   "30 1 * * *",
+			policy: fallLast,
+			from:   time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-11-03T01:30:00-08:00", "2019-11-04T01:30:00-08:00", "2019-11-05T01:30:00-08:00"},
+		},
+		{
+			name: "fall back: both with wildcard",
+			expr: "30 * * * *",
+			from: time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want: []string{"2019-11-03T00:30:00-07:00", "2019-11-03T01:30:00-07:00", "2019-11-03T01:30:00-08:00", "2019-11-03T02:30:00-08:00"},
+		},
+		{
+			name:   "fall back: first with wildcard",
+			expr:   "30 * * * *",
+			policy: fallFirst,
+			from:   time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-11-03T00:30:00-07:00", "2019-11-03T01:30:00-07:00", "2019-11-03T02:30:00-08:00", "2019-11-03T03:30:00-08:00"},
+		},
+		{
+			name:   "fall back: last with wildcard",
+			expr:   "30 * * * *",
+			policy: fallLast,
+			from:   time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-11-03T00:30:00-07:00", "2019-11-03T01:30:00-08:00", "2019-11-03T02:30:00-08:00", "2019-11-03T03:30:00-08:00"},
+		},
+		{
+			name:   "fall back: first after the change is not repeated",
+			expr:   "0 2 * * *",
+			policy: fallFirst,
+			from:   time.Date(2019, time.November, 3, 0, 0, 0, 0, losAngeles),
+			want:   []string{"2019-11-03T02:00:00-08:00", "2019-11-04T02:00:00-08:00", "2019-11-05T02:00:00-08:00"},
+		},
+
+		// Lord Howe moves from 2:00 to 2:30 on October 6, 2019, and from 2:00
+		// back to 1:30 on April 7, 2019.
+		{
+			name: "spring forward by half an hour: skip",
+			expr: "15 2 * * *",
+			from: time.Date(2019, time.October, 5, 0, 0, 0, 0, lordHowe),
+			want: []string{"2019-10-05T02:15:00+10:30", "2019-10-07T02:15:00+11:00", "2019-10-08T02:15:00+11:00"},
+		},
+		{
+			name:   "spring forward by half an hour: next valid",
+			expr:   "15 2 * * *",
+			policy: nextValid,
+			from:   time.Date(2019, time.October, 5, 0, 0, 0, 0, lordHowe),
+			want:   []string{"2019-10-05T02:15:00+10:30", "2019-10-06T02:30:00+11:00", "2019-10-07T02:15:00+11:00"},
+		},
+		{
+			name: "fall back by half an hour: both",
+			expr: "45 1 * * *",
+			from: time.Date(2019, time.April, 7, 0, 0, 0, 0, lordHowe),
+			want: []string{"2019-04-07T01:45:00+11:00", "2019-04-07T01:45:00+10:30", "2019-04-08T01:45:00+10:30"},
+		},
+		{
+			name:   "fall back by half an hour: first",
+			expr:   "45 1 * * *",
+			policy: fallFirst,
+			from:   time.Date(2019, time.April, 7, 0, 0, 0, 0, lordHowe),
+			want:   []string{"2019-04-07T01:45:00+11:00", "2019-04-08T01:45:00+10:30", "2019-04-09T01:45:00+10:30"},
+		},
+		{
+			name:   "fall back by half an hour: last",
+			expr:   "45 1 * * *",
+			policy: fallLast,
+			from:   time.Date(2019, time.April, 7, 0, 0, 0, 0, lordHowe),
+			want:   []string{"2019-04-07T01:45:00+10:30", "2019-04-08T01:45:00+10:30", "2019-04-09T01:45:00+10:30"},
+		},
+
+		// Sao Paulo moved from midnight to 1:00 on November 4, 2018, and from
+		// midnight back to 23:00 on February 17, 2018.
+		{
+			name:   "spring forward at midnight: next valid",
+			expr:   "30 0 * * *",
+			policy: nextValid,
+			from:   time.Date(2018, time.November, 3, 12, 0, 0, 0, saoPaulo),
+			want:   []string{"2018-11-04T01:00:00-02:00", "2018-11-05T00:30:00-02:00", "2018-11-06T00:30:00-02:00"},
+		},
+		{
+			name: "fall back at midnight: both",
+			expr: "30 23 * * *",
+			from: time.Date(2018, time.February, 17, 12, 0, 0, 0, saoPaulo),
+			want: []string{"2018-02-17T23:30:00-02:00", "2018-02-17T23:30:00-03:00", "2018-02-18T23:30:00-03:00"},
+		},
+		{
+			name:   "fall back at midnight: last",
+			expr:   "30 23 * * *",
+			policy: fallLast,
+			from:   time.Date(2018, time.February, 17, 12, 0, 0, 0, saoPaulo),
+			want:   []string{"2018-02-17T23:30:00-03:00", "2018-02-18T23:30:00-03:00", "2018-02-19T23:30:00-03:00"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.expr, WithDSTPolicy(tt.policy))
+			require.NoError(t, err)
+
+			nexts := expr.NextN(tt.from, uint(len(tt.want)))
+			var got []string
+			for _, next := range nexts {
+				got = append(got, next.Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.want, got)
+
+			last := nexts[len(nexts)-1]
+			prevs := expr.PrevN(last, uint(len(nexts)-1))
+			for i, prev := range prevs {
+				assert.Equal(t, nexts[len(nexts)-2-i], prev)
+			}
+			assert.Equal(t, nexts, expr.Between(tt.from, last, 0))
+		})
+	}
+}
+
+func TestWithDSTPolicy_Property(t *testing.T) {
+	policies := []DSTPolicy{
+		{},
+		nextValid,
+		fallFirst,
+		fallLast,
+		{SpringForward: SpringForwardNextValid, FallBack: FallBackLast},
+	}
+	fixtures := []struct {
+		loc   string
+		times [][3]int
+	}{
+		{"America/Los_Angeles", [][3]int{{2019, 3, 10}, {2019, 11, 3}}},
+		{"Australia/Lord_Howe", [][3]int{{2019, 4, 7}, {2019, 10, 6}}},
+		{"America/Sao_Paulo", [][3]int{{2018, 2, 17}, {2018, 11, 3}}},
+	}
+	cronExprs := []string{"* * * * *", "*/7 * * * *", "0 2 * * *", "* 1 * * *", "35 1 * * *", "5 2 * * *", "30 0 * * *", "5 23 * * *"}
+
+	for _, policy := range policies {
+		for _, cron := range cronExprs {
+			expr := MustParse(cron, WithDSTPolicy(policy))
+			for _, fixture := range fixtures {
+				loc := mustLoadLocation(fixture.loc)
+				for _, d := range fixture.times {
+					init := time.Date(d[0], time.Month(d[1]), d[2], 0, 0, 0, 0, loc).Add(-2 * time.Hour)
+					t.Run(fmt.Sprintf("%+v %v: %v", policy, cron, init), func(t *testing.T) {
+						prevNext := init
+						for start := init; start.Before(init.Add(6 * time.Hour)); start = start.Add(time.Minute) {
+							next := expr.Next(start)
+							require.True(t, next.After(start), "next(%v) = %v is not after start time", start, next)
+							require.False(t, next.Before(prevNext), "next(%v) = %v reverted back in time from %v", start, next, prevNext)
+							prev := expr.Prev(next)
+							require.True(t, !prev.After(start), "prev(%v) = %v is after %v", next, prev, start)
+							require.Equal(t, next, expr.Next(prev), "next(prev(%v))", next)
+							prevNext = next
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestWithDSTPolicy_Invalid(t *testing.T) {
+	_, err := Parse("0 0 * * *", WithDSTPolicy(DSTPolicy{SpringForward: "run"}))
+	assert.Error(t, err)
+	_, err = Parse("0 0 * * *", WithDSTPolicy(DSTPolicy{FallBack: "twice"}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,324 @@
+package cronexpr
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Spec is the serializable specification of an Expression, made up of the cron
+// line together with the CronFormat and ParseOptions it is parsed with.
+//
+// Spec can be used directly as a field of configuration structs, such as in
+// Kubernetes CRDs, and is also the JSON object form of an Expression.
+type Spec struct {
+	// Expression is the cron line, exactly as it was written.
+	Expression string `json:"expression"`
+
+	// Format is the CronFormat to parse Expression with, defaulting to CronFormatStandard.
+	Format CronFormat `json:"format,omitempty"`
+
+	// Hash specifies the hash options to parse Expression with, if any.
+	Hash *HashSpec `json:"hash,omitempty"`
+
+	// DayMatch is the DayMatchMode to parse Expression with, defaulting to DayMatchOr.
+	DayMatch DayMatchMode `json:"dayMatch,omitempty"`
+
+	// Location is the name of the time zone to parse Expression with, see WithLocation.
+	Location string `json:"location,omitempty"`
+
+	// DST is the DSTPolicy to parse Expression with, if not the default.
+	DST *DSTPolicy `json:"dst,omitempty"`
+}
+
+// HashSpec is the serializable form of the WithHash, WithHashEmptySeconds and WithHashFields options.
+type HashSpec struct {
+	// ID is the hash ID, see WithHash.
+	ID string `json:"id"`
+
+	// EmptySeconds enables WithHashEmptySeconds.
+	EmptySeconds bool `json:"emptySeconds,omitempty"`
+
+	// Fields enables WithHashFields.
+	Fields bool `json:"fields,omitempty"`
+}
+
+// Parse parses the Spec into a new Expression.
+func (s Spec) Parse() (*Expression, error) {
+	format := s.Format
+	if format == "" {
+		format = CronFormatStandard
+	}
+	options, err := s.options()
+	if err != nil {
+		return nil, err
+	}
+	return ParseForFormat(format, s.Expression, options...)
+}
+
+func (s Spec) options() ([]ParseOption, error) {
+	var options []ParseOption
+	if s.Hash != nil {
+		options = append(options, WithHash(s.Hash.ID))
+		if s.Hash.EmptySeconds {
+			options = append(options, WithHashEmptySeconds())
+		}
+		if s.Hash.Fields {
+			options = append(options, WithHashFields())
+		}
+	}
+	if s.DayMatch != "" {
+		options = append(options, WithDayMatchMode(s.DayMatch))
+	}
+	if s.Location != "" {
+		loc, err := time.LoadLocation(s.Location)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %s: %v", s.Location, err)
+		}
+		options = append(options, WithLocation(loc))
+	}
+	if s.DST != nil {
+		options = append(options, WithDSTPolicy(*s.DST))
+	}
+	return options, nil
+}
+
+// Spec returns the specification that the Expression was parsed from.
+//
+// Parsing the returned Spec gives the same schedule, with two exceptions.
+// Calendars given with WithCalendar and jitter given with WithJitter are not
+// part of a Spec, and are lost; MarshalText and MarshalJSON return an error
+// for an Expression with calendars or jitter instead. A location given with
+// WithLocation is kept by name only, so that one which time.LoadLocation
+// cannot load, such as a time.FixedZone, makes Spec.Parse return an error.
+func (expr *Expression) Spec() Spec {
+	spec := Spec{
+		Expression: expr.expression,
+	}
+	if expr.format != CronFormatStandard {
+		spec.Format = expr.format
+	}
+	if expr.hash != nil {
+		spec.Hash = &HashSpec{
+			ID:           expr.hash.hashID,
+			EmptySeconds: expr.hash.hashEmptySeconds,
+			Fields:       expr.hash.hashFields,
+		}
+	}
+	if expr.dayMatchMode != "" && expr.dayMatchMode != DayMatchOr {
+		spec.DayMatch = expr.dayMatchMode
+	}
+	// A time zone given by a prefix of the cron line is kept in Expression.
+	if expr.location != nil && !timeZoneFinder.MatchString(expr.expression) {
+		spec.Location = expr.location.String()
+	}
+	if expr.dstPolicy != (DSTPolicy{}) {
+		policy := expr.dstPolicy
+		spec.DST = &policy
+	}
+	return spec
+}
+
+/******************************************************************************/
+
+// Keys of the `KEY=value` tokens which may precede the cron line in the text form.
+const (
+	textKeyFormat        = "FORMAT"
+	textKeyHash          = "HASH"
+	textKeyHashOption    = "HASH_OPTION"
+	textKeyDayMatch      = "DAY_MATCH"
+	textKeyLocation      = "LOCATION"
+	textKeySpringForward = "SPRING_FORWARD"
+	textKeyFallBack      = "FALL_BACK"
+
+	textHashOptionEmptySeconds = "empty-seconds"
+	textHashOptionFields       = "fields"
+)
+
+var (
+	_ encoding.TextMarshaler   = &Expression{}
+	_ encoding.TextUnmarshaler = &Expression{}
+	_ json.Marshaler           = &Expression{}
+	_ json.Unmarshaler         = &Expression{}
+)
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// The text form is the original cron line, preceded by `KEY=value` tokens for
+// the CronFormat, hash options, DayMatchMode, location and DSTPolicy if needed,
+// for example:
+//
+//	FORMAT=quartz HASH=my-job HASH_OPTION=empty-seconds HASH_OPTION=fields H 0 12 ? * MON-FRI *
+//	DAY_MATCH=and LOCATION=Asia/Tokyo 0 0 13 * 5
+//	SPRING_FORWARD=next-valid FALL_BACK=first 30 1 * * *
+//
+// An Expression parsed with CronFormatStandard and no options is encoded as its
+// cron line only. The hash ID is escaped using URL query escaping.
+//
+// An error is returned if the Expression has options which cannot be encoded,
+// see Spec.
+func (expr *Expression) MarshalText() ([]byte, error) {
+	if err := expr.checkEncodable(); err != nil {
+		return nil, err
+	}
+	return []byte(expr.Spec().text()), nil
+}
+
+func (s Spec) text() string {
+	var tokens []string
+	if s.Format != "" && s.Format != CronFormatStandard {
+		tokens = append(tokens, textKeyFormat+"="+string(s.Format))
+	}
+	if s.Hash != nil {
+		tokens = append(tokens, textKeyHash+"="+url.QueryEscape(s.Hash.ID))
+		if s.Hash.EmptySeconds {
+			tokens = append(tokens, textKeyHashOption+"="+textHashOptionEmptySeconds)
+		}
+		if s.Hash.Fields {
+			tokens = append(tokens, textKeyHashOption+"="+textHashOptionFields)
+		}
+	}
+	if s.DayMatch != "" && s.DayMatch != DayMatchOr {
+		tokens = append(tokens, textKeyDayMatch+"="+string(s.DayMatch))
+	}
+	if s.Location != "" {
+		tokens = append(tokens, textKeyLocation+"="+s.Location)
+	}
+	if s.DST != nil {
+		if s.DST.SpringForward != "" {
+			tokens = append(tokens, textKeySpringForward+"="+string(s.DST.SpringForward))
+		}
+		if s.DST.FallBack != "" {
+			tokens = append(tokens, textKeyFallBack+"="+string(s.DST.FallBack))
+		}
+	}
+	return strings.Join(append(tokens, s.Expression), " ")
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, see MarshalText for the text form.
+func (expr *Expression) UnmarshalText(text []byte) error {
+	spec, err := parseSpecText(string(text))
+	if err != nil {
+		return err
+	}
+	return expr.unmarshalSpec(spec)
+}
+
+func parseSpecText(text string) (Spec, error) {
+	var spec Spec
+	for {
+		i := strings.IndexByte(text, ' ')
+		if i < 0 {
+			break
+		}
+		kv := strings.SplitN(text[:i], "=", 2)
+		if len(kv) != 2 {
+			break
+		}
+		switch kv[0] {
+		case textKeyFormat:
+			spec.Format = CronFormat(kv[1])
+		case textKeyHash:
+			id, err := url.QueryUnescape(kv[1])
+			if err != nil {
+				return spec, fmt.Errorf("invalid hash ID: %v", err)
+			}
+			if spec.Hash == nil {
+				spec.Hash = &HashSpec{}
+			}
+			spec.Hash.ID = id
+		case textKeyHashOption:
+			if spec.Hash == nil {
+				return spec, fmt.Errorf("%v requires %v", textKeyHashOption, textKeyHash)
+			}
+			switch kv[1] {
+			case textHashOptionEmptySeconds:
+				spec.Hash.EmptySeconds = true
+			case textHashOptionFields:
+				spec.Hash.Fields = true
+			default:
+				return spec, fmt.Errorf("unknown %v: %v", textKeyHashOption, kv[1])
+			}
+		case textKeyDayMatch:
+			spec.DayMatch = DayMatchMode(kv[1])
+		case textKeyLocation:
+			spec.Location = kv[1]
+		case textKeySpringForward:
+			if spec.DST == nil {
+				spec.DST = &DSTPolicy{}
+			}
+			spec.DST.SpringForward = SpringForwardPolicy(kv[1])
+		case textKeyFallBack:
+			if spec.DST == nil {
+				spec.DST = &DSTPolicy{}
+			}
+			spec.DST.FallBack = FallBackPolicy(kv[1])
+		default:
+			// Not one of ours, so this is where the cron line begins.
+			spec.Expression = text
+			return spec, nil
+		}
+		text = text[i+1:]
+	}
+	spec.Expression = text
+	return spec, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The Expression is encoded as its Spec, in the form of a JSON object.
+// An error is returned if the Expression has options which cannot be encoded,
+// see Spec.
+func (expr *Expression) MarshalJSON() ([]byte, error) {
+	if err := expr.checkEncodable(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(expr.Spec())
+}
+
+// checkEncodable returns an error if the Expression has options which are not
+// part of its Spec, so that decoding it would give a different schedule.
+func (expr *Expression) checkEncodable() error {
+	if len(expr.calendars) > 0 {
+		return errors.New("cannot encode an Expression with calendars")
+	}
+	if expr.jitter != 0 {
+		return errors.New("cannot encode an Expression with jitter")
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Both the JSON object form (see Spec) and a JSON string holding the text form
+// (see MarshalText) are accepted.
+func (expr *Expression) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return expr.UnmarshalText([]byte(text))
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	return expr.unmarshalSpec(spec)
+}
+
+func (expr *Expression) unmarshalSpec(spec Spec) error {
+	parsed, err := spec.Parse()
+	if err != nil {
+		return err
+	}
+	*expr = *parsed
+	return nil
+}
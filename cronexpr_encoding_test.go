@@ -0,0 +1,294 @@
+package cronexpr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_MarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		want    string
+	}{
+		{
+			name: "standard format without options",
+			expr: "0 0 * * 5L",
+			want: "0 0 * * 5L",
+		},
+		{
+			name: "predefined alias",
+			expr: "@daily",
+			want: "@daily",
+		},
+		{
+			name:   "quartz format",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 2 *",
+			want:   "FORMAT=quartz 0 0 11 ? * 2 *",
+		},
+		{
+			name:   "kubernetes format with time zone",
+			format: CronFormatKubernetes,
+			expr:   "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			want:   "FORMAT=kubernetes CRON_TZ=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name:    "hash",
+			expr:    "H H(0-7) * * *",
+			options: []ParseOption{WithHash("myid1")},
+			want:    "HASH=myid1 H H(0-7) * * *",
+		},
+		{
+			name:    "hash ID with spaces and symbols",
+			expr:    "H H(0-7) * * *",
+			options: []ParseOption{WithHash("my job=1 %")},
+			want:    "HASH=my+job%3D1+%25 H H(0-7) * * *",
+		},
+		{
+			name:    "empty hash ID",
+			expr:    "H H(0-7) * * *",
+			options: []ParseOption{WithHash("")},
+			want:    "HASH= H H(0-7) * * *",
+		},
+		{
+			name:    "day match mode",
+			expr:    "0 0 13 * 5",
+			options: []ParseOption{WithDayMatchMode(DayMatchAnd)},
+			want:    "DAY_MATCH=and 0 0 13 * 5",
+		},
+		{
+			name:    "default day match mode",
+			expr:    "0 0 13 * 5",
+			options: []ParseOption{WithDayMatchMode(DayMatchOr)},
+			want:    "0 0 13 * 5",
+		},
+		{
+			name: "time zone prefix",
+			expr: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			want: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name:    "location",
+			expr:    "0 9 * * *",
+			options: []ParseOption{WithLocation(mustLoadLocation("Asia/Tokyo"))},
+			want:    "LOCATION=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name:    "dst policy",
+			expr:    "30 1 * * *",
+			options: []ParseOption{WithDSTPolicy(DSTPolicy{SpringForward: SpringForwardNextValid, FallBack: FallBackFirst})},
+			want:    "SPRING_FORWARD=next-valid FALL_BACK=first 30 1 * * *",
+		},
+		{
+			name:    "default dst policy",
+			expr:    "30 1 * * *",
+			options: []ParseOption{WithDSTPolicy(DSTPolicy{SpringForward: SpringForwardSkip, FallBack: FallBackBoth})},
+			want:    "30 1 * * *",
+		},
+		{
+			name:    "all options",
+			format:  CronFormatQuartz,
+			expr:    "H 0 12 ? * MON-FRI *",
+			options: []ParseOption{WithHash("myid1"), WithHashEmptySeconds(), WithHashFields()},
+			want:    "FORMAT=quartz HASH=myid1 HASH_OPTION=empty-seconds HASH_OPTION=fields H 0 12 ? * MON-FRI *",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			expr, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.NoError(t, err)
+
+			text, err := expr.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(text))
+
+			decoded := &Expression{}
+			require.NoError(t, decoded.UnmarshalText(text))
+			assert.Equal(t, expr.Spec(), decoded.Spec())
+			assertSameSchedule(t, expr, decoded)
+
+			data, err := json.Marshal(expr)
+			require.NoError(t, err)
+			decoded = &Expression{}
+			require.NoError(t, json.Unmarshal(data, decoded))
+			assert.Equal(t, expr.Spec(), decoded.Spec())
+			assertSameSchedule(t, expr, decoded)
+		})
+	}
+}
+
+func TestExpression_UnmarshalText_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"* * * *",
+		"FORMAT=unknown 0 0 * * *",
+		"HASH_OPTION=fields H * * * *",
+		"HASH=myid1 HASH_OPTION=unknown H * * * *",
+		"HASH=%zz H * * * *",
+		"DAY_MATCH=xor 0 0 13 * 5",
+		"LOCATION=Unknown/Zone 0 0 * * *",
+		"FALL_BACK=twice 0 0 * * *",
+		"H * * * *",
+	} {
+		assert.Error(t, (&Expression{}).UnmarshalText([]byte(text)), "UnmarshalText(%q)", text)
+	}
+}
+
+func TestExpression_MarshalJSON(t *testing.T) {
+	expr, err := ParseForFormat(CronFormatQuartz, "H 0 12 ? * MON-FRI *", WithHash("myid1"), WithHashFields())
+	require.NoError(t, err)
+	data, err := json.Marshal(expr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"H 0 12 ? * MON-FRI *","format":"quartz","hash":{"id":"myid1","fields":true}}`, string(data))
+
+	data, err = json.Marshal(MustParse("0 0 * * *"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"0 0 * * *"}`, string(data))
+
+	data, err = json.Marshal(MustParse("0 0 13 * 5", WithDayMatchMode(DayMatchAnd)))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"0 0 13 * 5","dayMatch":"and"}`, string(data))
+
+	data, err = json.Marshal(MustParse("0 9 * * *", WithLocation(mustLoadLocation("Asia/Tokyo"))))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"0 9 * * *","location":"Asia/Tokyo"}`, string(data))
+
+	data, err = json.Marshal(MustParse("30 1 * * *", WithDSTPolicy(DSTPolicy{FallBack: FallBackLast})))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"30 1 * * *","dst":{"fallBack":"last"}}`, string(data))
+}
+
+func TestExpression_UnmarshalJSON(t *testing.T) {
+	type config struct {
+		Schedule *Expression `json:"schedule,omitempty"`
+	}
+	from := time.Date(2020, time.December, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		data    string
+		want    *Expression
+		wantErr bool
+	}{
+		{
+			name: "string",
+			data: `{"schedule": "0 0 11 ? * 2 *"}`,
+			want: MustParse("0 0 11 ? * 2 *"),
+		},
+		{
+			name: "string in text form",
+			data: `{"schedule": "FORMAT=quartz HASH=myid1 H 0 11 ? * 2 *"}`,
+			want: MustParseForFormat(CronFormatQuartz, "H 0 11 ? * 2 *", WithHash("myid1")),
+		},
+		{
+			name: "object",
+			data: `{"schedule": {"expression": "H 11 ? * 2", "format": "quartz", "hash": {"id": "myid1", "emptySeconds": true}}}`,
+			want: MustParseForFormat(CronFormatQuartz, "H 11 ? * 2", WithHash("myid1"), WithHashEmptySeconds()),
+		},
+		{
+			name: "null",
+			data: `{"schedule": null}`,
+		},
+		{
+			name: "missing",
+			data: `{}`,
+		},
+		{
+			name:    "invalid expression",
+			data:    `{"schedule": "0 0 11 ? * 8 *"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid object",
+			data:    `{"schedule": {"expression": 5}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			data:    `{"schedule": {"expression": "0 0 11 ? * 2 *", "format": "unknown"}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var c config
+			err := json.Unmarshal([]byte(tt.data), &c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, c.Schedule)
+				return
+			}
+			require.NotNil(t, c.Schedule)
+			assert.Equal(t, tt.want.Spec(), c.Schedule.Spec())
+			assert.Equal(t, tt.want.NextN(from, 5), c.Schedule.NextN(from, 5))
+		})
+	}
+}
+
+func TestSpec_Parse(t *testing.T) {
+	spec := Spec{
+		Expression: "H 11 ? * 2",
+		Format:     CronFormatQuartz,
+		Hash:       &HashSpec{ID: "myid1", EmptySeconds: true, Fields: true},
+	}
+	expr, err := spec.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, spec, expr.Spec())
+	assertSameSchedule(t, MustParseForFormat(CronFormatQuartz, "H 11 ? * 2",
+		WithHash("myid1"), WithHashEmptySeconds(), WithHashFields()), expr)
+
+	expr, err = Spec{Expression: "0 0 11 ? * 2 *"}.Parse()
+	require.NoError(t, err)
+	assertSameSchedule(t, MustParse("0 0 11 ? * 2 *"), expr)
+
+	_, err = Spec{Expression: "H 11 ? * 2"}.Parse()
+	assert.Error(t, err)
+}
+
+func TestExpression_Spec_Lossy(t *testing.T) {
+	// Calendars and jitter are not part of the Spec.
+	calendar := DateCalendar(Date{Month: time.September, Day: 2})
+	expr := MustParse("0 9 * * *", WithCalendar(calendar), WithJitter(time.Minute, "my-job"))
+	assert.Equal(t, Spec{Expression: "0 9 * * *"}, expr.Spec())
+	parsed, err := expr.Spec().Parse()
+	require.NoError(t, err)
+	assertSameSchedule(t, MustParse("0 9 * * *"), parsed)
+
+	// A location which cannot be loaded by name cannot be parsed back.
+	expr = MustParse("0 9 * * *", WithLocation(time.FixedZone("UTC+9", 9*60*60)))
+	assert.Equal(t, "UTC+9", expr.Spec().Location)
+	_, err = expr.Spec().Parse()
+	assert.Error(t, err)
+}
+
+func TestExpression_MarshalText_NotEncodable(t *testing.T) {
+	expr := MustParse("0 9 * * *", WithCalendar(DateCalendar(Date{Month: time.December, Day: 25})))
+	_, err := expr.MarshalText()
+	assert.Error(t, err)
+	_, err = json.Marshal(expr)
+	assert.Error(t, err)
+}
+
+func TestExpression_MarshalText_Jitter(t *testing.T) {
+	expr := MustParse("0 9 * * *", WithJitter(time.Hour, "my-job"))
+	_, err := expr.MarshalText()
+	assert.Error(t, err)
+	_, err = json.Marshal(expr)
+	assert.Error(t, err)
+}
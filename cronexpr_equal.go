@@ -0,0 +1,112 @@
+package cronexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Equal returns whether two Expressions have the same schedule as parsed,
+// regardless of how their cron lines are written or which CronFormat they are
+// written in. For example, `*/15 9-17 * * 1-5`, `0,15,30,45 9-17 * * mon-fri`
+// and `0 0/15 9-17 ? * MON-FRI *` with CronFormatQuartz are equal.
+//
+// The field sets, the day-of-month and day-of-week restrictions and their
+// special markers (`L`, `W`, `#`), the DayMatchMode where it applies, the time
+// zone, the DSTPolicy, the calendars and the jitter with its seed are
+// compared. Hashes are compared by their resolved values only. Calendars
+// created by this package are compared by the dates or schedule they exclude,
+// and other calendars by identity.
+func (expr *Expression) Equal(other *Expression) bool {
+	if expr == nil || other == nil {
+		return expr == other
+	}
+	return expr.canonical() == other.canonical()
+}
+
+// Fingerprint returns a hash of the schedule of the Expression, such that equal
+// Expressions (see Equal) have the same fingerprint. It is stable across
+// processes, unless the Expression has calendars other than those created by
+// this package, and can be used as a map key to deduplicate Expressions.
+//
+// Expressions which are not equal may rarely have the same fingerprint.
+func (expr *Expression) Fingerprint() uint64 {
+	return HashString(expr.canonical())
+}
+
+// canonical returns a representation of the schedule of the Expression which is
+// the same for all Expressions having the same schedule.
+func (expr *Expression) canonical() string {
+	var b strings.Builder
+	if expr.every != 0 {
+		fmt.Fprintf(&b, "every=%d,%d,%t", int64(expr.every), int64(expr.everyPhase), expr.everyRelative)
+	} else {
+		expr.writeCanonicalFields(&b)
+	}
+	if len(expr.calendars) > 0 {
+		fmt.Fprintf(&b, " cal=%s", canonicalCalendar(expr.calendars))
+	}
+	if expr.jitter != 0 {
+		fmt.Fprintf(&b, " jitter=%d,%q", int64(expr.jitter), expr.jitterSeed)
+	}
+	return b.String()
+}
+
+// writeCanonicalFields writes the canonical representation of the fields of
+// the Expression, and of the options which apply to them.
+func (expr *Expression) writeCanonicalFields(b *strings.Builder) {
+	fmt.Fprintf(b, "s=%x m=%x h=%x mon=%x", uint64(expr.seconds), uint64(expr.minutes), uint64(expr.hours), uint64(expr.months))
+
+	domRestricted, dowRestricted := expr.dayRestrictions()
+	if domRestricted {
+		fmt.Fprintf(b, " dom=%x,%x,%t,%x,%t,%x", uint64(expr.daysOfMonth), uint64(expr.workdaysOfMonth),
+			expr.lastDayOfMonth, uint64(expr.daysBeforeLastDayOfMonth),
+			expr.lastWorkdayOfMonth, uint64(expr.workdaysBeforeLastDayOfMonth))
+	}
+	if dowRestricted {
+		// `5#2` and `5L` are redundant if `5` is in the field.
+		specific, last := expr.specificWeekDaysOfWeek, expr.lastWeekDaysOfWeek
+		for dows := expr.daysOfWeek; dows != 0; {
+			v := dows.first()
+			dows &^= 1 << uint(v)
+			specific &^= everyWeek << uint(v)
+			last &^= 1 << uint(v)
+		}
+		fmt.Fprintf(b, " dow=%x,%x,%x", uint64(expr.daysOfWeek), uint64(specific), uint64(last))
+	}
+	if domRestricted && dowRestricted && expr.dayMatchMode == DayMatchAnd {
+		b.WriteString(" and")
+	}
+
+	if years := expr.yearList; len(years) > 0 && len(years) < yearDescriptor.max-yearDescriptor.min+1 {
+		fmt.Fprintf(b, " y=%v", years)
+	}
+	if expr.location != nil {
+		fmt.Fprintf(b, " tz=%s", expr.location)
+	}
+	if expr.dstPolicy != (DSTPolicy{}) {
+		fmt.Fprintf(b, " dst=%s,%s", expr.dstPolicy.SpringForward, expr.dstPolicy.FallBack)
+	}
+}
+
+// dayRestrictions returns whether the day-of-month and day-of-week fields
+// restrict the matching days, given each other and the DayMatchMode. A field
+// having all of its values is the same as `*`, unless both fields are combined
+// with DayMatchOr, where it makes every day match.
+func (expr *Expression) dayRestrictions() (dom, dow bool) {
+	dom, dow = expr.daysOfMonthRestricted, expr.daysOfWeekRestricted
+	allDom := expr.daysOfMonth.count() == domDescriptor.max
+	allDow := expr.daysOfWeek.count() == 7
+	if dom && dow && expr.dayMatchMode != DayMatchAnd {
+		if allDom || allDow {
+			return false, false
+		}
+		return true, true
+	}
+	if allDom {
+		dom = false
+	}
+	if allDow {
+		dow = false
+	}
+	return dom, dow
+}
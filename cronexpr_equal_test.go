@@ -0,0 +1,272 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Equal(t *testing.T) {
+	tokyo := mustLoadLocation("Asia/Tokyo")
+	tests := []struct {
+		name  string
+		a, b  *Expression
+		equal bool
+	}{
+		{
+			name:  "same cron line",
+			a:     MustParse("0 0 * * *"),
+			b:     MustParse("0 0 * * *"),
+			equal: true,
+		},
+		{
+			name:  "interval and list",
+			a:     MustParse("*/15 9-17 * * 1-5"),
+			b:     MustParse("0,15,30,45 9,10,11,12,13,14,15,16,17 * * mon-fri"),
+			equal: true,
+		},
+		{
+			name:  "across formats",
+			a:     MustParse("*/15 9-17 * * 1-5"),
+			b:     MustParseForFormat(CronFormatQuartz, "0 0/15 9-17 ? * MON-FRI *"),
+			equal: true,
+		},
+		{
+			name:  "predefined alias",
+			a:     MustParse("@daily"),
+			b:     MustParse("0 0 0 * * * *"),
+			equal: true,
+		},
+		{
+			name:  "question mark and wildcard",
+			a:     MustParseForFormat(CronFormatQuartz, "0 0 12 ? * * *"),
+			b:     MustParse("0 12 * * *"),
+			equal: true,
+		},
+		{
+			name:  "all days of month",
+			a:     MustParse("0 0 1-31 * *"),
+			b:     MustParse("0 0 * * *"),
+			equal: true,
+		},
+		{
+			name:  "all days of month or day of week",
+			a:     MustParse("0 0 1-31 * 5"),
+			b:     MustParse("0 0 * * *"),
+			equal: true,
+		},
+		{
+			name:  "all days of month and day of week",
+			a:     MustParse("0 0 1-31 * 5", WithDayMatchMode(DayMatchAnd)),
+			b:     MustParse("0 0 * * 5"),
+			equal: true,
+		},
+		{
+			name:  "day of week 7 and 0",
+			a:     MustParse("0 0 * * 7"),
+			b:     MustParse("0 0 * * sun"),
+			equal: true,
+		},
+		{
+			name:  "redundant nth day of week",
+			a:     MustParse("0 0 * * 5,5#2,5L"),
+			b:     MustParse("0 0 * * 5"),
+			equal: true,
+		},
+		{
+			name:  "all years",
+			a:     MustParse("0 0 * * * *"),
+			b:     MustParse("0 0 * * * 1-9999"),
+			equal: true,
+		},
+		{
+			name:  "hash resolved",
+			a:     MustParse("H H * * *", WithHash("myid1")),
+			b:     MustParse(MustParse("H H * * *", WithHash("myid1")).String()),
+			equal: true,
+		},
+		{
+			name:  "day match mode without both day fields",
+			a:     MustParse("0 0 13 * *", WithDayMatchMode(DayMatchAnd)),
+			b:     MustParse("0 0 13 * *"),
+			equal: true,
+		},
+		{
+			name:  "time zone prefix and location",
+			a:     MustParse("CRON_TZ=Asia/Tokyo 0 9 * * *"),
+			b:     MustParse("0 9 * * *", WithLocation(tokyo)),
+			equal: true,
+		},
+		{
+			name:  "default dst policy",
+			a:     MustParse("30 1 * * *", WithDSTPolicy(DSTPolicy{SpringForward: SpringForwardSkip, FallBack: FallBackBoth})),
+			b:     MustParse("30 1 * * *"),
+			equal: true,
+		},
+		{
+			name:  "interval",
+			a:     MustParse("@every 90m"),
+			b:     MustParse("@every 1h30m 0s"),
+			equal: true,
+		},
+		{
+			name: "different minutes",
+			a:    MustParse("0 0 * * *"),
+			b:    MustParse("1 0 * * *"),
+		},
+		{
+			name: "different seconds",
+			a:    MustParse("0 0 * * * *"),
+			b:    MustParseForFormat(CronFormatQuartz, "30 0 0 * * ? *"),
+		},
+		{
+			name: "last day of month and day 31",
+			a:    MustParse("0 0 L * *"),
+			b:    MustParse("0 0 31 * *"),
+		},
+		{
+			name: "work day and day",
+			a:    MustParse("0 0 15W * *"),
+			b:    MustParse("0 0 15 * *"),
+		},
+		{
+			name: "nth day of week and day of week",
+			a:    MustParse("0 0 * * 5#2"),
+			b:    MustParse("0 0 * * 5"),
+		},
+		{
+			name: "day match mode",
+			a:    MustParse("0 0 13 * 5", WithDayMatchMode(DayMatchAnd)),
+			b:    MustParse("0 0 13 * 5"),
+		},
+		{
+			name: "years",
+			a:    MustParse("0 0 * * * 2030"),
+			b:    MustParse("0 0 * * * *"),
+		},
+		{
+			name: "time zone",
+			a:    MustParse("CRON_TZ=Asia/Tokyo 0 9 * * *"),
+			b:    MustParse("0 9 * * *"),
+		},
+		{
+			name: "dst policy",
+			a:    MustParse("30 1 * * *", WithDSTPolicy(DSTPolicy{FallBack: FallBackFirst})),
+			b:    MustParse("30 1 * * *"),
+		},
+		{
+			name: "interval phase",
+			a:    MustParse("@every 1h 15m"),
+			b:    MustParse("@every 1h"),
+		},
+		{
+			name: "interval and fields",
+			a:    MustParse("@every 1h"),
+			b:    MustParse("@hourly"),
+		},
+		{
+			name: "same calendars",
+			a: MustParse("0 9 * * *", WithCalendar(
+				DateCalendar(Date{Month: time.December, Day: 25}, Date{Month: time.January, Day: 1}),
+				CronCalendar(MustParse("* * * * 0,6")),
+			)),
+			b: MustParse("0 9 * * *", WithCalendar(
+				CronCalendar(MustParse("* * * * sat,sun")),
+				DateCalendar(Date{Month: time.January, Day: 1}, Date{Month: time.December, Day: 25}),
+			)),
+			equal: true,
+		},
+		{
+			name: "calendar",
+			a:    MustParse("0 9 * * *", WithCalendar(DateCalendar(Date{Month: time.March, Day: 2}))),
+			b:    MustParse("0 9 * * *"),
+		},
+		{
+			name: "different calendars",
+			a:    MustParse("0 9 * * *", WithCalendar(DateCalendar(Date{Month: time.March, Day: 2}))),
+			b:    MustParse("0 9 * * *", WithCalendar(DateCalendar(Date{Month: time.March, Day: 3}))),
+		},
+		{
+			name: "different date ranges",
+			a:    MustParse("0 9 * * *", WithCalendar(mustDateRangeCalendar(t, "03-02", "03-05"))),
+			b:    MustParse("0 9 * * *", WithCalendar(mustDateRangeCalendar(t, "03-02", "03-06"))),
+		},
+		{
+			name:  "same jitter",
+			a:     MustParse("0 9 * * *", WithJitter(time.Hour, "my-job")),
+			b:     MustParse("0 9 * * 0-6", WithJitter(time.Hour, "my-job")),
+			equal: true,
+		},
+		{
+			name: "jitter",
+			a:    MustParse("0 9 * * *", WithJitter(time.Hour, "my-job")),
+			b:    MustParse("0 9 * * *"),
+		},
+		{
+			name: "jitter seed",
+			a:    MustParse("0 9 * * *", WithJitter(time.Hour, "my-job")),
+			b:    MustParse("0 9 * * *", WithJitter(time.Hour, "other-job")),
+		},
+		{
+			name: "interval jitter",
+			a:    MustParse("@every 1h", WithJitter(time.Minute, "my-job")),
+			b:    MustParse("@every 1h", WithJitter(2*time.Minute, "my-job")),
+		},
+		{
+			name: "relative interval",
+			a:    MustParseForFormat(CronFormatKubernetes, "@every 1h"),
+			b:    MustParse("@every 1h"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, tt.a.Equal(tt.b))
+			assert.Equal(t, tt.equal, tt.b.Equal(tt.a))
+			assert.Equal(t, tt.equal, tt.a.Fingerprint() == tt.b.Fingerprint())
+			if tt.equal {
+				from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+				assert.Equal(t, tt.a.NextN(from, 20), tt.b.NextN(from, 20))
+			}
+		})
+	}
+}
+
+func TestExpression_Equal_Nil(t *testing.T) {
+	var expr *Expression
+	assert.True(t, expr.Equal(nil))
+	assert.False(t, expr.Equal(MustParse("* * * * *")))
+	assert.False(t, MustParse("* * * * *").Equal(nil))
+}
+
+func TestExpression_Fingerprint(t *testing.T) {
+	// The fingerprint does not change across processes.
+	expr, err := Parse("*/15 9-17 * * 1-5")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x9b75ddd3b4bb20a1), expr.Fingerprint())
+
+	set := map[uint64]string{}
+	for _, cron := range []string{"* * * * *", "0 * * * *", "0 0 * * *", "0 0 1 * *", "0 0 * * 0", "0 0 L * *", "0 0 LW * *", "0 0 * * 5L", "0 0 * * 5#3", "@every 1h"} {
+		fingerprint := MustParse(cron).Fingerprint()
+		assert.NotContains(t, set, fingerprint, "%s has the same fingerprint as %s", cron, set[fingerprint])
+		set[fingerprint] = cron
+	}
+}
+
+type otherCalendar struct {
+	name string
+}
+
+func (*otherCalendar) Exclusion(t time.Time) (start, end time.Time, ok bool) {
+	return time.Time{}, time.Time{}, false
+}
+
+func TestExpression_Equal_OtherCalendars(t *testing.T) {
+	// Calendars which are not created by this package are compared by identity.
+	calendar := &otherCalendar{name: "holidays"}
+	a := MustParse("0 9 * * *", WithCalendar(calendar))
+	assert.True(t, a.Equal(MustParse("0 9 * * *", WithCalendar(calendar))))
+	assert.False(t, a.Equal(MustParse("0 9 * * *", WithCalendar(&otherCalendar{name: "holidays"}))))
+}
@@ -0,0 +1,118 @@
+package cronexpr
+
+import (
+	"fmt"
+)
+
+// ParseErrorReason is a machine-readable code describing why a cron expression could not be parsed.
+type ParseErrorReason string
+
+const (
+	// ReasonTooFewFields means that the cron line has less than the minimum number of fields.
+	ReasonTooFewFields ParseErrorReason = "too-few-fields"
+
+	// ReasonTooManyFields means that the cron line has more than the maximum number of fields of its CronFormat.
+	ReasonTooManyFields ParseErrorReason = "too-many-fields"
+
+	// ReasonMissingDirective means that a field does not contain any directive, e.g. `,`.
+	ReasonMissingDirective ParseErrorReason = "missing-directive"
+
+	// ReasonUnknownToken means that a directive could not be understood for the field, e.g. `1-X` for minutes.
+	ReasonUnknownToken ParseErrorReason = "unknown-token"
+
+	// ReasonBelowMinimum means that a value or the beginning of a range is below the field's minimum value, e.g. `0` for days of month.
+	ReasonBelowMinimum ParseErrorReason = "below-minimum"
+
+	// ReasonAboveMaximum means that a value or the end of a range is above the field's maximum value, e.g. `1-60` for minutes.
+	ReasonAboveMaximum ParseErrorReason = "above-maximum"
+
+	// ReasonInvertedRange means that the beginning of a range is beyond its end, e.g. `20-10`.
+	ReasonInvertedRange ParseErrorReason = "inverted-range"
+
+	// ReasonStepOutOfRange means that the interval of a directive is below 1 or larger than the field's maximum value.
+	ReasonStepOutOfRange ParseErrorReason = "step-out-of-range"
+
+	// ReasonHashWithoutOption means that `H` was used without parsing with WithHash.
+	ReasonHashWithoutOption ParseErrorReason = "hash-without-option"
+
+	// ReasonDayFieldConflict means that the day-of-month and day-of-week fields cannot be
+	// combined as given, e.g. when both are specified with CronFormatQuartz.
+	ReasonDayFieldConflict ParseErrorReason = "day-field-conflict"
+
+	// ReasonUnknownTimeZone means that the time zone of a `CRON_TZ=` or `TZ=` prefix could not be loaded.
+	ReasonUnknownTimeZone ParseErrorReason = "unknown-time-zone"
+
+	// ReasonInvalidDuration means that the interval of `@every` is not a valid time.Duration.
+	ReasonInvalidDuration ParseErrorReason = "invalid-duration"
+)
+
+// ParseError is returned when a cron expression is malformed, and describes which part of
+// the cron line is at fault.
+//
+// Use errors.As to retrieve a *ParseError from an error returned by the Parse functions.
+type ParseError struct {
+	// Field is the name of the field, such as "day-of-week".
+	// Empty if the error does not belong to a single field.
+	Field string
+
+	// Offset is the byte offset of the offending directive in the cron line.
+	// If the cron line uses a predefined alias such as `@daily`, offsets refer to the
+	// line after the alias was expanded.
+	Offset int
+
+	// Length is the length in bytes of the offending directive in the cron line.
+	Length int
+
+	// Directive is the offending directive, as it appears in the cron line.
+	Directive string
+
+	// Reason is the machine-readable reason for the error.
+	Reason ParseErrorReason
+
+	// Err is the underlying error, which holds the human-readable message.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newDirectiveError returns a *ParseError for the given directive, located relative to its field.
+// The field name and the absolute position are filled in by parseField.
+func newDirectiveError(reason ParseErrorReason, directive *cronDirective, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Offset: directive.sbeg,
+		Length: directive.send - directive.sbeg,
+		Reason: reason,
+		Err:    fmt.Errorf(format, a...),
+	}
+}
+
+// parseField calls handler with the field of cron found at index, and locates any
+// *ParseError returned by the handler within cron.
+func parseField(cron string, index []int, name string, handler func(s string) error) error {
+	err := handler(cron[index[0]:index[1]])
+	if perr, ok := err.(*ParseError); ok {
+		perr.Field = name
+		perr.Offset += index[0]
+		perr.Directive = cron[perr.Offset : perr.Offset+perr.Length]
+	}
+	return err
+}
+
+// newLineError returns a *ParseError for the part of the cron line between the
+// offsets beg and end, which does not belong to a single field.
+func newLineError(reason ParseErrorReason, cronLine string, beg, end int, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Offset:    beg,
+		Length:    end - beg,
+		Directive: cronLine[beg:end],
+		Reason:    reason,
+		Err:       fmt.Errorf(format, a...),
+	}
+}
@@ -0,0 +1,240 @@
+package cronexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		want    ParseError
+	}{
+		{
+			name: "too few fields",
+			expr: "* * * *",
+			want: ParseError{Offset: 7, Reason: ReasonTooFewFields},
+		},
+		{
+			name: "missing directive",
+			expr: "0 , * * *",
+			want: ParseError{Field: "hour", Offset: 2, Length: 1, Directive: ",", Reason: ReasonMissingDirective},
+		},
+		{
+			name: "value above maximum",
+			expr: "60 * * * *",
+			want: ParseError{Field: "minute", Offset: 0, Length: 2, Directive: "60", Reason: ReasonAboveMaximum},
+		},
+		{
+			name: "end of range above maximum",
+			expr: "1-60 * * * *",
+			want: ParseError{Field: "minute", Offset: 0, Length: 4, Directive: "1-60", Reason: ReasonAboveMaximum},
+		},
+		{
+			name: "value below minimum",
+			expr: "0 0 1,0 * *",
+			want: ParseError{Field: "day-of-month", Offset: 6, Length: 1, Directive: "0", Reason: ReasonBelowMinimum},
+		},
+		{
+			name: "beginning of interval below minimum",
+			expr: "0 0 * 0-6/2 *",
+			want: ParseError{Field: "month", Offset: 6, Length: 5, Directive: "0-6/2", Reason: ReasonBelowMinimum},
+		},
+		{
+			name: "unknown token in second",
+			expr: "x 0 0 * * * *",
+			want: ParseError{Field: "second", Offset: 0, Length: 1, Directive: "x", Reason: ReasonUnknownToken},
+		},
+		{
+			name: "unknown token in list",
+			expr: "0 0 1,2,3 * MON,Xyz",
+			want: ParseError{Field: "day-of-week", Offset: 16, Length: 3, Directive: "Xyz", Reason: ReasonUnknownToken},
+		},
+		{
+			name: "unknown token in day-of-month",
+			expr: "0 0 1,15X * *",
+			want: ParseError{Field: "day-of-month", Offset: 6, Length: 3, Directive: "15X", Reason: ReasonUnknownToken},
+		},
+		{
+			name: "year above maximum",
+			expr: "0 0 0 1 1 * 2000,10000",
+			want: ParseError{Field: "year", Offset: 17, Length: 5, Directive: "10000", Reason: ReasonAboveMaximum},
+		},
+		{
+			name: "below minimum",
+			expr: "0 0 0 1 1 * 0",
+			want: ParseError{Field: "year", Offset: 12, Length: 1, Directive: "0", Reason: ReasonBelowMinimum},
+		},
+		{
+			name:   "unknown token with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 1,Xyz *",
+			want:   ParseError{Field: "day-of-week", Offset: 13, Length: 3, Directive: "Xyz", Reason: ReasonUnknownToken},
+		},
+		{
+			name:   "day of week below minimum with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 0-7 *",
+			want:   ParseError{Field: "day-of-week", Offset: 11, Length: 3, Directive: "0-7", Reason: ReasonBelowMinimum},
+		},
+		{
+			name:   "day of week above maximum with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 8 *",
+			want:   ParseError{Field: "day-of-week", Offset: 11, Length: 1, Directive: "8", Reason: ReasonAboveMaximum},
+		},
+		{
+			name:   "day field conflict with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 12 15 * MON *",
+			want:   ParseError{Field: "day-of-week", Offset: 12, Length: 3, Directive: "MON", Reason: ReasonDayFieldConflict},
+		},
+		{
+			name:   "* in place of ? with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 12 * * 6L",
+			want:   ParseError{Field: "day-of-week", Offset: 11, Length: 2, Directive: "6L", Reason: ReasonDayFieldConflict},
+		},
+		{
+			name: "offset from last day of month above maximum",
+			expr: "0 0 L-31 * *",
+			want: ParseError{Field: "day-of-month", Offset: 4, Length: 4, Directive: "L-31", Reason: ReasonAboveMaximum},
+		},
+		{
+			name: "work day offset from last day of month below minimum",
+			expr: "0 0 1,L-0W * *",
+			want: ParseError{Field: "day-of-month", Offset: 6, Length: 4, Directive: "L-0W", Reason: ReasonBelowMinimum},
+		},
+		{
+			name: "inverted range",
+			expr: "0  10-5 * * *",
+			want: ParseError{Field: "hour", Offset: 3, Length: 4, Directive: "10-5", Reason: ReasonInvertedRange},
+		},
+		{
+			name: "step out of range",
+			expr: "*/60 * * * * * *",
+			want: ParseError{Field: "second", Offset: 0, Length: 4, Directive: "*/60", Reason: ReasonStepOutOfRange},
+		},
+		{
+			name: "step out of range in list",
+			expr: "0 0 1-31/0,L * *",
+			want: ParseError{Field: "day-of-month", Offset: 4, Length: 6, Directive: "1-31/0", Reason: ReasonStepOutOfRange},
+		},
+		{
+			name: "hash without WithHash",
+			expr: "0 h(0-7) * * *",
+			want: ParseError{Field: "hour", Offset: 2, Length: 6, Directive: "h(0-7)", Reason: ReasonHashWithoutOption},
+		},
+		{
+			name:    "invalid hash interval",
+			expr:    "0 H/80 ? * *",
+			options: []ParseOption{WithHash("myid1")},
+			want:    ParseError{Field: "hour", Offset: 2, Length: 4, Directive: "H/80", Reason: ReasonStepOutOfRange},
+		},
+		{
+			name: "invalid interval",
+			expr: "@every 90",
+			want: ParseError{Offset: 7, Length: 2, Directive: "90", Reason: ReasonInvalidDuration},
+		},
+		{
+			name: "interval with a fraction of a second",
+			expr: "@every 1500ms",
+			want: ParseError{Offset: 7, Length: 6, Directive: "1500ms", Reason: ReasonInvalidDuration},
+		},
+		{
+			name: "missing interval",
+			expr: "@every ",
+			want: ParseError{Offset: 6, Length: 1, Directive: " ", Reason: ReasonInvalidDuration},
+		},
+		{
+			name: "hashed phase without WithHash",
+			expr: "@every 1h H",
+			want: ParseError{Offset: 10, Length: 1, Directive: "H", Reason: ReasonHashWithoutOption},
+		},
+		{
+			name: "phase not less than interval",
+			expr: "@every 1h 60m",
+			want: ParseError{Offset: 10, Length: 3, Directive: "60m", Reason: ReasonAboveMaximum},
+		},
+		{
+			name: "negative phase",
+			expr: "@every 1h -5m",
+			want: ParseError{Offset: 10, Length: 3, Directive: "-5m", Reason: ReasonBelowMinimum},
+		},
+		{
+			name:   "seconds with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "0 0 0 * * * *",
+			want:   ParseError{Offset: 10, Length: 1, Directive: "*", Reason: ReasonTooManyFields},
+		},
+		{
+			name:   "sunday as 7 with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "CRON_TZ=UTC 0 0 * * 1,7",
+			want:   ParseError{Field: "day-of-week", Offset: 22, Length: 1, Directive: "7", Reason: ReasonAboveMaximum},
+		},
+		{
+			name:   "last day of month with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "0 0 L * *",
+			want:   ParseError{Field: "day-of-month", Offset: 4, Length: 1, Directive: "L", Reason: ReasonUnknownToken},
+		},
+		{
+			name: "missing time zone",
+			expr: " CRON_TZ= 0 0 * * *",
+			want: ParseError{Offset: 1, Length: 8, Directive: "CRON_TZ=", Reason: ReasonUnknownTimeZone},
+		},
+		{
+			name:   "unknown time zone with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "CRON_TZ=Nowhere/City 0 0 * * *",
+			want:   ParseError{Offset: 8, Length: 12, Directive: "Nowhere/City", Reason: ReasonUnknownTimeZone},
+		},
+		{
+			name:   "invalid interval with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "@every 5 minutes",
+			want:   ParseError{Offset: 7, Length: 9, Directive: "5 minutes", Reason: ReasonInvalidDuration},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			_, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.Error(t, err)
+
+			var perr *ParseError
+			require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &perr), "not a *ParseError: %v", err)
+			assert.Equal(t, tt.want.Field, perr.Field)
+			assert.Equal(t, tt.want.Offset, perr.Offset)
+			assert.Equal(t, tt.want.Length, perr.Length)
+			assert.Equal(t, tt.want.Directive, perr.Directive)
+			assert.Equal(t, tt.want.Reason, perr.Reason)
+			assert.Equal(t, tt.want.Directive, tt.expr[perr.Offset:perr.Offset+perr.Length])
+			assert.Equal(t, perr.Err.Error(), err.Error())
+		})
+	}
+}
+
+var errTestOption = errors.New("test option error")
+
+type failingParseOption struct{}
+
+func (failingParseOption) Apply(expr *Expression) error { return errTestOption }
+func (failingParseOption) GetPriority() int             { return 0 }
+
+func TestParse_OptionError(t *testing.T) {
+	_, err := Parse("* * * * *", failingParseOption{})
+	assert.True(t, errors.Is(err, errTestOption), "not wrapping the option error: %v", err)
+}
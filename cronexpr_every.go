@@ -0,0 +1,113 @@
+package cronexpr
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	everyFinder = regexp.MustCompile(`^\s*@every(\s|$)`)
+	everyLayout = regexp.MustCompile(`^\s*@every\s+(\S+)(?:\s+(\S+))?\s*$`)
+)
+
+// everyHandler parses an `@every` interval schedule, made up of `@every`, the
+// interval as a time.Duration, and optionally the phase, which is either a
+// time.Duration less than the interval, or `H` to hash it. For example,
+// `@every 1h30m`, `@every 1h 15m` or `@every 1h H`.
+//
+// The time instants of the schedule are those at a whole number of intervals
+// plus the phase from the Unix epoch, such that they do not depend on the time
+// Next is called with.
+func (expr *Expression) everyHandler(cronLine string) error {
+	pairs := everyLayout.FindStringSubmatchIndex(cronLine)
+	if len(pairs) == 0 {
+		beg := strings.Index(cronLine, "@every") + len("@every")
+		return newLineError(ReasonInvalidDuration, cronLine, beg, len(cronLine),
+			"expected an interval and an optional phase after @every: '%s'", strings.TrimSpace(cronLine[beg:]))
+	}
+
+	// `1h30m`
+	interval := cronLine[pairs[2]:pairs[3]]
+	every, err := time.ParseDuration(interval)
+	if err != nil {
+		return newLineError(ReasonInvalidDuration, cronLine, pairs[2], pairs[3], "invalid interval: %v", err)
+	}
+	if every < time.Second || every%time.Second != 0 {
+		return newLineError(ReasonInvalidDuration, cronLine, pairs[2], pairs[3],
+			"interval must be a whole number of seconds, and at least one second: %s", interval)
+	}
+	expr.every = every
+
+	if pairs[4] < 0 {
+		return nil
+	}
+
+	// `H`
+	phase := cronLine[pairs[4]:pairs[5]]
+	if strings.ToLower(phase) == "h" {
+		if expr.hash == nil {
+			return newLineError(ReasonHashWithoutOption, cronLine, pairs[4], pairs[5], "hash requested without using WithHash: %v", phase)
+		}
+		expr.everyPhase = time.Duration(expr.hash.GetValueForField(0, int(every/time.Second)-1, "every")) * time.Second
+		return nil
+	}
+
+	// `15m`
+	expr.everyPhase, err = time.ParseDuration(phase)
+	if err != nil {
+		return newLineError(ReasonInvalidDuration, cronLine, pairs[4], pairs[5], "invalid phase: %v", err)
+	}
+	if expr.everyPhase%time.Second != 0 {
+		return newLineError(ReasonInvalidDuration, cronLine, pairs[4], pairs[5], "phase must be a whole number of seconds: %s", phase)
+	}
+	if expr.everyPhase < 0 {
+		return newLineError(ReasonBelowMinimum, cronLine, pairs[4], pairs[5], "phase (%s) below minimum (0s): %s", expr.everyPhase, phase)
+	}
+	if expr.everyPhase >= every {
+		return newLineError(ReasonAboveMaximum, cronLine, pairs[4], pairs[5], "phase (%s) not less than interval (%s): %s", expr.everyPhase, every, phase)
+	}
+	return nil
+}
+
+// nextEvery returns the earliest time instant of an `@every` interval schedule
+// following `fromTime`.
+func (expr *Expression) nextEvery(fromTime time.Time) time.Time {
+	// As in github.com/robfig/cron, one interval after `fromTime`, with its
+	// fraction of a second dropped.
+	if expr.everyRelative {
+		return fromTime.Add(expr.every - time.Duration(fromTime.Nanosecond())*time.Nanosecond)
+	}
+
+	every, phase := int64(expr.every/time.Second), int64(expr.everyPhase/time.Second)
+	n := floorDiv(fromTime.Unix()-phase, every) + 1
+	return time.Unix(phase+n*every, 0).In(fromTime.Location())
+}
+
+// prevEvery returns the latest time instant of an `@every` interval schedule
+// preceding `fromTime`.
+func (expr *Expression) prevEvery(fromTime time.Time) time.Time {
+	// One interval before `fromTime`, with its fraction of a second dropped,
+	// such that nextEvery of it is `fromTime` again if it has no fraction of a
+	// second.
+	if expr.everyRelative {
+		return fromTime.Add(-expr.every - time.Duration(fromTime.Nanosecond())*time.Nanosecond)
+	}
+
+	last := fromTime.Unix()
+	if fromTime.Nanosecond() == 0 {
+		last--
+	}
+	every, phase := int64(expr.every/time.Second), int64(expr.everyPhase/time.Second)
+	n := floorDiv(last-phase, every)
+	return time.Unix(phase+n*every, 0).In(fromTime.Location())
+}
+
+// floorDiv returns a divided by b, rounded towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,107 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvery(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		from    time.Time
+		want    []string
+	}{
+		{
+			name: "interval",
+			expr: "@every 90m",
+			want: []string{"2021-03-01T01:30:00Z", "2021-03-01T03:00:00Z", "2021-03-01T04:30:00Z", "2021-03-01T06:00:00Z"},
+		},
+		{
+			name: "interval not dividing a day",
+			expr: "@every 7m",
+			want: []string{"2021-03-01T00:01:00Z", "2021-03-01T00:08:00Z", "2021-03-01T00:15:00Z", "2021-03-01T00:22:00Z"},
+		},
+		{
+			name: "phase",
+			expr: "@every 1h 15m",
+			from: from.Add(20 * time.Minute),
+			want: []string{"2021-03-01T01:15:00Z", "2021-03-01T02:15:00Z", "2021-03-01T03:15:00Z", "2021-03-01T04:15:00Z"},
+		},
+		{
+			name:    "hashed phase",
+			expr:    "@every 1h H",
+			options: []ParseOption{WithHash("myid1")},
+			want:    []string{"2021-03-01T00:14:59Z", "2021-03-01T01:14:59Z", "2021-03-01T02:14:59Z", "2021-03-01T03:14:59Z"},
+		},
+		{
+			name:    "hashed phase with WithHashFields",
+			expr:    "@every 1h H",
+			options: []ParseOption{WithHash("myid1"), WithHashFields()},
+			want:    []string{"2021-03-01T00:56:19Z", "2021-03-01T01:56:19Z", "2021-03-01T02:56:19Z", "2021-03-01T03:56:19Z"},
+		},
+		{
+			name:   "interval with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "@every 6h",
+			from:   from.Add(time.Second),
+			want:   []string{"2021-03-01T06:00:00Z", "2021-03-01T12:00:00Z", "2021-03-01T18:00:00Z", "2021-03-02T00:00:00Z"},
+		},
+		{
+			name: "aligned to the Unix epoch rather than the time zone",
+			expr: "@every 1h",
+			from: time.Date(2021, time.March, 1, 10, 0, 0, 0, kolkata),
+			want: []string{"2021-03-01T10:30:00+05:30", "2021-03-01T11:30:00+05:30", "2021-03-01T12:30:00+05:30", "2021-03-01T13:30:00+05:30"},
+		},
+		{
+			name: "before the Unix epoch",
+			expr: "@every 7m",
+			from: time.Date(1969, time.December, 31, 23, 50, 0, 0, time.UTC),
+			want: []string{"1969-12-31T23:53:00Z", "1970-01-01T00:00:00Z", "1970-01-01T00:07:00Z", "1970-01-01T00:14:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			expr, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.NoError(t, err)
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+
+			nexts := expr.NextN(start, 4)
+			var got []string
+			for _, next := range nexts {
+				assert.Equal(t, start.Location(), next.Location())
+				got = append(got, next.Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.want, got)
+
+			// A fraction of a second does not change the time instants.
+			assert.Equal(t, nexts[0], expr.Next(start.Add(time.Millisecond)))
+
+			// Prev and Between agree with Next.
+			assert.Equal(t, []time.Time{nexts[2], nexts[1], nexts[0]}, expr.PrevN(nexts[3], 3))
+			assert.Equal(t, nexts, expr.Between(start, nexts[3], 0))
+
+			// The normalized form has the same time instants, without the options.
+			normalized, err := ParseForFormat(format, expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, nexts, normalized.NextN(start, 4))
+		})
+	}
+}
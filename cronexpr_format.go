@@ -16,6 +16,10 @@ const (
 	// Uses the Quartz scheduler format.
 	// See http://www.quartz-scheduler.org/documentation/quartz-2.3.0/tutorials/crontrigger.html#format.
 	CronFormatQuartz CronFormat = "quartz"
+
+	// Uses the format of Kubernetes CronJobs, which is that of github.com/robfig/cron's standard parser.
+	// See https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#schedule-syntax.
+	CronFormatKubernetes CronFormat = "kubernetes"
 )
 
 var ErrUnknownFormat = errors.New("unknown CronFormat")
@@ -29,6 +33,8 @@ type formattedExpression struct {
 // expressionHandler supports delegation of field parsing to custom handlers.
 // *Expression should always implement this interface, which provides the default implementation.
 type expressionHandler interface {
+	lineHandler(cronLine string) (string, error)
+	domFieldHandler(s string) error
 	dowFieldHandler(s string) error
 }
 
@@ -36,7 +42,7 @@ var _ expressionHandler = &Expression{}
 
 func newFormattedExpression(format CronFormat) (*formattedExpression, error) {
 	e := &formattedExpression{
-		Expression: &Expression{},
+		Expression: &Expression{format: format, dayMasks: new(dayMaskCache)},
 	}
 
 	switch format {
@@ -44,6 +50,8 @@ func newFormattedExpression(format CronFormat) (*formattedExpression, error) {
 		e.handler = e.Expression
 	case CronFormatQuartz:
 		e.handler = &quartzExpression{Expression: e.Expression}
+	case CronFormatKubernetes:
+		e.handler = &kubernetesExpression{Expression: e.Expression}
 	default:
 		return nil, ErrUnknownFormat
 	}
@@ -51,6 +59,14 @@ func newFormattedExpression(format CronFormat) (*formattedExpression, error) {
 	return e, nil
 }
 
+func (e *formattedExpression) lineHandler(cronLine string) (string, error) {
+	return e.handler.lineHandler(cronLine)
+}
+
+func (e *formattedExpression) domFieldHandler(s string) error {
+	return e.handler.domFieldHandler(s)
+}
+
 func (e *formattedExpression) dowFieldHandler(s string) error {
 	return e.handler.dowFieldHandler(s)
 }
@@ -1,6 +1,7 @@
 package cronexpr
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -61,13 +62,33 @@ func TestFormattedExpressionsParsing(t *testing.T) {
 			format: CronFormatQuartz,
 		},
 		{
-			name:   "parsing * for day of week",
-			expr:   "0 0 2 * 1-7 *",
+			name:   "parsing 6 fields with CronFormatQuartz",
+			expr:   "0 0 12 ? * 6L",
 			format: CronFormatQuartz,
 		},
+		// Before 6 fields were read as seconds to day of week, and `?` was
+		// required in one of the day fields, these were read as minute to year.
+		// See "Upgrading" in README.md.
 		{
-			name:   "parsing * for day of week without specifying the day",
-			expr:   "0 0 11 ? *",
+			name:    "parsing * for day of week",
+			expr:    "0 0 2 * 1-7 *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:   "parsing * for day of week, upgraded",
+			expr:   "0 0 0 2 * ?",
+			format: CronFormatQuartz,
+		},
+		{
+			name:    "parsing * for day of week without specifying the day",
+			expr:    "0 0 11 ? *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:   "parsing * for day of week without specifying the day, upgraded",
+			expr:   "0 0 0 11 * ?",
 			format: CronFormatQuartz,
 		},
 		{
@@ -239,6 +260,238 @@ func TestFormattedExpressions(t *testing.T) {
 				{"2020-12-14 00:00:00", "2020-12-15 11:00:00"},
 			},
 		},
+		{
+			name:   "all days of week is no restriction with CronFormatQuartz",
+			expr:   "0 0 0 ? * 1-7",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-03 00:00:00", "2021-03-04 00:00:00"},
+			},
+		},
+		{
+			name:   "@yearly with CronFormatQuartz",
+			expr:   "@yearly",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2022-01-01 00:00:00"},
+			},
+		},
+		{
+			name:   "@annually with CronFormatQuartz",
+			expr:   "@annually",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2022-01-01 00:00:00"},
+			},
+		},
+		{
+			name:   "@monthly with CronFormatQuartz",
+			expr:   "@monthly",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-04-01 00:00:00"},
+			},
+		},
+		{
+			name:   "@weekly with CronFormatQuartz",
+			expr:   "@weekly",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-07 00:00:00"},
+			},
+		},
+		{
+			name:   "@daily with CronFormatQuartz",
+			expr:   "@daily",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-02 00:00:00"},
+			},
+		},
+		{
+			name:   "@hourly with CronFormatQuartz",
+			expr:   "@hourly",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-01 01:00:00"},
+			},
+		},
+		{
+			name:   "time zone and @daily with CronFormatQuartz",
+			expr:   "CRON_TZ=UTC @daily",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-02 00:00:00"},
+			},
+		},
+		// Examples from the Quartz documentation, see
+		// http://www.quartz-scheduler.org/documentation/quartz-2.3.0/tutorials/crontrigger.html#example-cron-expressions.
+		{
+			name:   "quartz: fire at 10:15am every day",
+			expr:   "0 15 10 ? * *",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-01 10:15:00"},
+				{"2021-03-01 10:15:00", "2021-03-02 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am every day with a year field",
+			expr:   "0 15 10 * * ? *",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 10:15:00", "2021-03-02 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am every day during the year 2005",
+			expr:   "0 15 10 * * ? 2005",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2004-06-01 00:00:00", "2005-01-01 10:15:00"},
+				{"2005-12-31 10:15:00", "0001-01-01 00:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire every 5 minutes starting at 2pm and at 6pm",
+			expr:   "0 0/5 14,18 * * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 14:57:00", "2021-03-01 18:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 2:10pm and at 2:44pm every Wednesday in March",
+			expr:   "0 10,44 14 ? 3 WED",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-03 14:10:00"},
+				{"2021-03-31 14:45:00", "2022-03-02 14:10:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am every weekday",
+			expr:   "0 15 10 ? * MON-FRI",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-06 00:00:00", "2021-03-08 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am on the last day of every month",
+			expr:   "0 15 10 L * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-31 10:15:00"},
+				{"2021-04-01 00:00:00", "2021-04-30 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am on the 2nd-to-last day of every month",
+			expr:   "0 15 10 L-2 * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-02-01 00:00:00", "2021-02-26 10:15:00"},
+				{"2021-03-01 00:00:00", "2021-03-29 10:15:00"},
+				{"2021-03-30 00:00:00", "2021-04-28 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 6pm on the weekday nearest the 2nd-to-last day of every month",
+			expr:   "0 0 18 L-2W * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-02-01 00:00:00", "2021-02-26 18:00:00"},
+				{"2021-03-01 00:00:00", "2021-03-29 18:00:00"},
+				{"2021-07-01 00:00:00", "2021-07-29 18:00:00"},
+				{"2021-10-01 00:00:00", "2021-10-29 18:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at noon on the last weekday of every month",
+			expr:   "0 0 12 LW * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-02-01 00:00:00", "2021-02-26 12:00:00"},
+				{"2021-04-01 00:00:00", "2021-04-30 12:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am on the last Friday of every month",
+			expr:   "0 15 10 ? * 6L",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-26 10:15:00"},
+				{"2021-03-27 00:00:00", "2021-04-30 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am on every last Friday of every month during 2002-2005",
+			expr:   "0 15 10 ? * 6L 2002-2005",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2005-12-01 00:00:00", "2005-12-30 10:15:00"},
+				{"2005-12-30 10:15:00", "0001-01-01 00:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at 10:15am on the third Friday of every month",
+			expr:   "0 15 10 ? * 6#3",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-19 10:15:00"},
+				{"2021-03-20 00:00:00", "2021-04-16 10:15:00"},
+			},
+		},
+		{
+			name:   "quartz: fire at noon every 5 days starting on the first day of the month",
+			expr:   "0 0 12 1/5 * ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 12:00:00", "2021-03-06 12:00:00"},
+			},
+		},
+		{
+			name:   "quartz: fire every November 11th at 11:11am",
+			expr:   "0 11 11 11 11 ?",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-11-11 11:11:00"},
+			},
+		},
+		{
+			name:   "last Friday with CronFormatQuartz",
+			expr:   "0 0 12 ? * 6L",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-26 12:00:00"},
+			},
+		},
+		{
+			name:   "third Friday with CronFormatQuartz",
+			expr:   "0 0 12 ? * 6#3",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-19 12:00:00"},
+			},
+		},
+		{
+			name:   "L alone is Saturday with CronFormatQuartz",
+			expr:   "0 0 12 ? * L *",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-01 00:00:00", "2021-03-06 12:00:00"},
+				{"2021-03-06 12:00:00", "2021-03-13 12:00:00"},
+			},
+		},
+		{
+			name:   "first Monday with CronFormatQuartz",
+			expr:   "0 0 12 ? * MON#1 *",
+			format: CronFormatQuartz,
+			times: []crontimes{
+				{"2021-03-02 00:00:00", "2021-04-05 12:00:00"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -333,6 +586,60 @@ func TestInvalidFormatExpressionsParsing(t *testing.T) {
 			format:  CronFormatQuartz,
 			wantErr: true,
 		},
+		{
+			name:    "parsing both day of month and day of week with CronFormatQuartz",
+			expr:    "0 0 12 15 * MON *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing * for both day of month and day of week with CronFormatQuartz",
+			expr:    "0 0 12 * * *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing * in place of ? with CronFormatQuartz",
+			expr:    "0 0 12 * * 6L",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing ? for both day of month and day of week with CronFormatQuartz",
+			expr:    "0 0 12 ? * ? *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing L and day of week with CronFormatQuartz",
+			expr:    "0 0 12 L * 6L *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing L-0 with CronFormatQuartz",
+			expr:    "0 0 12 L-0 * ? *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing L-31 with CronFormatQuartz",
+			expr:    "0 0 12 L-31 * ? *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing invalid last day of week with CronFormatQuartz",
+			expr:    "0 0 12 ? * 8L *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
+		{
+			name:    "parsing invalid week of month with CronFormatQuartz",
+			expr:    "0 0 12 ? * 6#6 *",
+			format:  CronFormatQuartz,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -346,3 +653,166 @@ func TestInvalidFormatExpressionsParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEntryPoints(t *testing.T) {
+	type entryPoint struct {
+		name    string
+		formats []CronFormat
+		parse   func(format CronFormat, cronLine string, options ...ParseOption) (*Expression, error)
+	}
+	mustParse := func(f func() *Expression) (expr *Expression, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = r.(error)
+			}
+		}()
+		return f(), nil
+	}
+	entryPoints := []entryPoint{
+		{
+			name:    "ParseForFormat",
+			formats: []CronFormat{CronFormatStandard, CronFormatQuartz, CronFormatKubernetes},
+			parse:   ParseForFormat,
+		},
+		{
+			name:    "MustParseForFormat",
+			formats: []CronFormat{CronFormatStandard, CronFormatQuartz, CronFormatKubernetes},
+			parse: func(format CronFormat, cronLine string, options ...ParseOption) (*Expression, error) {
+				return mustParse(func() *Expression { return MustParseForFormat(format, cronLine, options...) })
+			},
+		},
+		{
+			name:    "Parse",
+			formats: []CronFormat{CronFormatStandard},
+			parse: func(_ CronFormat, cronLine string, options ...ParseOption) (*Expression, error) {
+				return Parse(cronLine, options...)
+			},
+		},
+		{
+			name:    "MustParse",
+			formats: []CronFormat{CronFormatStandard},
+			parse: func(_ CronFormat, cronLine string, options ...ParseOption) (*Expression, error) {
+				return mustParse(func() *Expression { return MustParse(cronLine, options...) })
+			},
+		},
+	}
+
+	optionSets := []struct {
+		name    string
+		options func() []ParseOption
+	}{
+		{"no options", func() []ParseOption { return nil }},
+		{"WithHash", func() []ParseOption { return []ParseOption{WithHash("myid1")} }},
+		{"WithHash WithHashEmptySeconds", func() []ParseOption {
+			return []ParseOption{WithHash("myid1"), WithHashEmptySeconds()}
+		}},
+		{"WithHash WithHashFields", func() []ParseOption {
+			return []ParseOption{WithHash("myid1"), WithHashFields()}
+		}},
+		{"WithHash WithHashEmptySeconds WithHashFields", func() []ParseOption {
+			return []ParseOption{WithHashFields(), WithHashEmptySeconds(), WithHash("myid1")}
+		}},
+		{"WithHashEmptySeconds", func() []ParseOption { return []ParseOption{WithHashEmptySeconds()} }},
+		{"WithHashFields", func() []ParseOption { return []ParseOption{WithHashFields()} }},
+	}
+
+	// Each case lists the first time instant following 2020-12-12 00:00:00,
+	// keyed by format and then by option set; an empty string means a parse error.
+	tests := []struct {
+		expr string
+		want map[CronFormat]map[string]string
+	}{
+		{
+			expr: "0 11 ? * 2",
+			want: map[CronFormat]map[string]string{
+				CronFormatStandard: {
+					"no options":                    "2020-12-15 11:00:00",
+					"WithHash":                      "2020-12-15 11:00:00",
+					"WithHash WithHashEmptySeconds": "2020-12-15 11:00:59",
+					"WithHash WithHashFields":       "2020-12-15 11:00:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-15 11:00:44",
+					"WithHashEmptySeconds":                         "2020-12-15 11:00:00",
+					"WithHashFields":                               "2020-12-15 11:00:00",
+				},
+				CronFormatKubernetes: {
+					"no options":                    "2020-12-15 11:00:00",
+					"WithHash":                      "2020-12-15 11:00:00",
+					"WithHash WithHashEmptySeconds": "2020-12-15 11:00:59",
+					"WithHash WithHashFields":       "2020-12-15 11:00:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-15 11:00:44",
+					"WithHashEmptySeconds":                         "2020-12-15 11:00:00",
+					"WithHashFields":                               "2020-12-15 11:00:00",
+				},
+				CronFormatQuartz: {
+					"no options":                    "2020-12-14 11:00:00",
+					"WithHash":                      "2020-12-14 11:00:00",
+					"WithHash WithHashEmptySeconds": "2020-12-14 11:00:59",
+					"WithHash WithHashFields":       "2020-12-14 11:00:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-14 11:00:44",
+					"WithHashEmptySeconds":                         "2020-12-14 11:00:00",
+					"WithHashFields":                               "2020-12-14 11:00:00",
+				},
+			},
+		},
+		{
+			expr: "H H(0-7) ? * 2",
+			want: map[CronFormat]map[string]string{
+				CronFormatStandard: {
+					"no options":                    "",
+					"WithHash":                      "2020-12-15 03:59:00",
+					"WithHash WithHashEmptySeconds": "2020-12-15 03:59:59",
+					"WithHash WithHashFields":       "2020-12-15 07:36:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-15 07:36:44",
+					"WithHashEmptySeconds":                         "",
+					"WithHashFields":                               "",
+				},
+				CronFormatKubernetes: {
+					"no options":                    "",
+					"WithHash":                      "2020-12-15 03:59:00",
+					"WithHash WithHashEmptySeconds": "2020-12-15 03:59:59",
+					"WithHash WithHashFields":       "2020-12-15 07:36:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-15 07:36:44",
+					"WithHashEmptySeconds":                         "",
+					"WithHashFields":                               "",
+				},
+				CronFormatQuartz: {
+					"no options":                    "",
+					"WithHash":                      "2020-12-14 03:59:00",
+					"WithHash WithHashEmptySeconds": "2020-12-14 03:59:59",
+					"WithHash WithHashFields":       "2020-12-14 07:36:00",
+					"WithHash WithHashEmptySeconds WithHashFields": "2020-12-14 07:36:44",
+					"WithHashEmptySeconds":                         "",
+					"WithHashFields":                               "",
+				},
+			},
+		},
+	}
+
+	from := time.Date(2020, time.December, 12, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		for _, ep := range entryPoints {
+			for _, format := range ep.formats {
+				for _, set := range optionSets {
+					tt, ep, format, set := tt, ep, format, set
+					t.Run(fmt.Sprintf("%v/%v/%v/%v", ep.name, format, set.name, tt.expr), func(t *testing.T) {
+						want := tt.want[format][set.name]
+						expr, err := ep.parse(format, tt.expr, set.options()...)
+						if want == "" {
+							if err == nil {
+								t.Errorf(`%v(%v, "%v") did not return error`, ep.name, format, tt.expr)
+							}
+							return
+						}
+						if err != nil {
+							t.Errorf(`%v(%v, "%v") returned "%v"`, ep.name, format, tt.expr, err)
+							return
+						}
+						if got := expr.Next(from).Format("2006-01-02 15:04:05"); got != want {
+							t.Errorf(`(%v(%v, "%v")).Next("%v") = "%v", got "%v"`, ep.name, format, tt.expr, from, want, got)
+						}
+					})
+				}
+			}
+		}
+	}
+}
@@ -1,8 +1,7 @@
 package cronexpr
 
 import (
-	"fmt"
-	"sync/atomic"
+	"sync"
 
 	"github.com/cespare/xxhash/v2"
 )
@@ -40,11 +39,14 @@ type hashSecondsParseOption struct {
 }
 
 func (h *hashSecondsParseOption) Apply(expr *Expression) error {
-	expr.hash.hashEmptySeconds = true
+	if expr.hash != nil {
+		expr.hash.hashEmptySeconds = true
+	}
 	return nil
 }
 
 // WithHashFields returns a ParseOption that will also hash the field name to make hashes less deterministic.
+// Requires to be used in conjunction with WithHash, otherwise it will have no effect.
 // For example, `H H * * * * *` will always hash the seconds and minutes to the same value, for example
 // 00:37:37, 01:37:37, etc.
 // Enabling this option will append additional keys to be hashed to introduce additional non-determinism.
@@ -57,7 +59,9 @@ type hashFieldsParseOption struct {
 }
 
 func (h *hashFieldsParseOption) Apply(expr *Expression) error {
-	expr.hash.hashFields = true
+	if expr.hash != nil {
+		expr.hash.hashFields = true
+	}
 	return nil
 }
 
@@ -75,6 +79,7 @@ type hash struct {
 	hashFields bool
 
 	// Memoized value that was previously computed.
+	mu     sync.Mutex
 	value  uint64
 	hashed bool
 }
@@ -101,11 +106,13 @@ func (h *hash) GetValue(min, max int) int {
 }
 
 func (h *hash) getValue() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	if !h.hashed {
-		atomic.StoreUint64(&h.value, HashString(h.hashID))
+		h.value = HashString(h.hashID)
 		h.hashed = true
 	}
-	return atomic.LoadUint64(&h.value)
+	return h.value
 }
 
 // AddSuffix returns a new hash with suffix added.
@@ -122,8 +129,8 @@ func HashString(str string) uint64 {
 	return xxhash.Sum64String(str)
 }
 
-// makeErrorNoHashInput returns a new error for to display the string
+// makeErrorNoHashInput returns a new error for to display the directive
 // containing a H token when there is no hash input.
-func makeErrorNoHashInput(s string) error {
-	return fmt.Errorf("hash requested without using WithHash: %v", s)
+func makeErrorNoHashInput(directive *cronDirective) error {
+	return newDirectiveError(ReasonHashWithoutOption, directive, "hash requested without using WithHash: %v", directive.item)
 }
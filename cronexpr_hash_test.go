@@ -2,6 +2,7 @@ package cronexpr
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -55,6 +56,21 @@ func Test_hash_GetValue(t *testing.T) {
 	}
 }
 
+func Test_hash_GetValue_Concurrent(t *testing.T) {
+	h := &hash{hashID: "myid3"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := h.GetValue(0, 15); got != 13 {
+				t.Errorf("GetValue() = %v, want %v", got, 13)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestHashExpressions(t *testing.T) {
 	tests := []struct {
 		name  string
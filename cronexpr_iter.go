@@ -0,0 +1,144 @@
+package cronexpr
+
+import (
+	"time"
+)
+
+// Iterator iterates over the time instants matching an Expression within a
+// time window. It is created with Expression.Iter.
+//
+// An Iterator is not safe for concurrent use, but any number of Iterators may
+// be used concurrently over the same Expression.
+type Iterator struct {
+	expr  *Expression
+	until time.Time
+	last  time.Time
+	done  bool
+	loc   *time.Location
+
+	// State of the day the last time instant is in. Within a day without
+	// daylight saving changes, the next time instant is found by advancing the
+	// hour, minute and second of the last one, rather than by going through
+	// Next.
+	dayStart time.Time
+	dayValid bool
+	hour     int
+	minute   int
+	second   int
+}
+
+// Iter returns an Iterator over the time instants following `fromTime` which
+// match the cron expression `expr`, up to and including `untilTime`.
+//
+// If `untilTime` is the zero value of time.Time, the iteration is only bounded
+// by the last matching time instant of the expression.
+//
+// The `time.Location` of the time instants is the same as that of `fromTime`.
+func (expr *Expression) Iter(fromTime, untilTime time.Time) *Iterator {
+	return &Iterator{
+		expr:  expr,
+		until: untilTime,
+		last:  fromTime,
+		done:  fromTime.IsZero(),
+		loc:   fromTime.Location(),
+	}
+}
+
+// Next returns the next matching time instant, and true. Once there is no
+// more matching time instant within the time window, the zero value of
+// time.Time and false are returned.
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done {
+		return time.Time{}, false
+	}
+
+	t, ok := it.advance()
+	if !ok {
+		t = it.expr.Next(it.last)
+		it.resetDay(t)
+	}
+
+	if t.IsZero() || (!it.until.IsZero() && t.After(it.until)) {
+		it.done = true
+		return time.Time{}, false
+	}
+	it.last = t
+	return t, true
+}
+
+// advance returns the time instant following the last one within the same
+// day, if it is known.
+func (it *Iterator) advance() (time.Time, bool) {
+	if !it.dayValid {
+		return time.Time{}, false
+	}
+	expr := it.expr
+
+	var ok bool
+	if it.second, ok = expr.seconds.next(it.second + 1); !ok {
+		it.second = expr.seconds.first()
+		if it.minute, ok = expr.minutes.next(it.minute + 1); !ok {
+			it.minute = expr.minutes.first()
+			if it.hour, ok = expr.hours.next(it.hour + 1); !ok {
+				it.dayValid = false
+				return time.Time{}, false
+			}
+		}
+	}
+
+	// There is no offset change within the day, so all wall clock times
+	// are a fixed duration away from midnight.
+	return it.dayStart.Add(time.Duration(it.hour)*time.Hour +
+		time.Duration(it.minute)*time.Minute +
+		time.Duration(it.second)*time.Second).In(it.loc), true
+}
+
+// resetDay sets the state of the day `t` is in, if iterating within that day
+// can be done without going through Next.
+func (it *Iterator) resetDay(t time.Time) {
+	it.dayValid = false
+	if t.IsZero() || it.expr.every != 0 || len(it.expr.calendars) > 0 || it.expr.jitter != 0 {
+		return
+	}
+	if it.expr.location != nil {
+		t = t.In(it.expr.location)
+	}
+	if timeZoneInDay(t) {
+		return
+	}
+
+	it.dayStart = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	it.hour, it.minute, it.second = t.Clock()
+	it.dayValid = true
+}
+
+/******************************************************************************/
+
+// Between returns the time instants following `fromTime` which match the cron
+// expression `expr`, up to and including `untilTime`.
+//
+// At most `limit` time instants are returned, unless `limit` is zero or
+// negative, in which case all time instants within the time window are
+// returned. If `untilTime` is the zero value of time.Time, the time window has
+// no end, and `limit` must then be positive: nil is returned otherwise, rather
+// than every time instant up to the year 9999.
+//
+// The time instants in the returned slice are in chronological ascending order.
+// The `time.Location` of the returned time instants is the same as that of
+// `fromTime`.
+func (expr *Expression) Between(fromTime, untilTime time.Time, limit int) []time.Time {
+	if untilTime.IsZero() && limit <= 0 {
+		return nil
+	}
+
+	var times []time.Time
+	it := expr.Iter(fromTime, untilTime)
+	for limit <= 0 || len(times) < limit {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
@@ -0,0 +1,152 @@
+package cronexpr
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIter_MatchesNext(t *testing.T) {
+	var locations []*time.Location
+	for _, name := range []string{"UTC", "America/Los_Angeles", "Australia/Lord_Howe", "America/Sao_Paulo"} {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+		locations = append(locations, loc)
+	}
+
+	cronExprs := []string{
+		"* * * * *",
+		"*/7 * * * *",
+		"0 2 * * *",
+		"30 1 * * *",
+		"15 */2 * * *",
+		"*/20 * 1,2 * * * *",
+		"0 0/15 * * * * *",
+		"0 0 L * *",
+		"30 3 15W 3/3 *",
+		"0 0 * * 5L",
+	}
+	froms := [][3]int{{2019, 3, 9}, {2019, 11, 2}, {2019, 4, 6}, {2018, 11, 3}, {2018, 12, 31}}
+
+	for _, cron := range cronExprs {
+		expr := MustParse(cron)
+		for _, loc := range locations {
+			for _, d := range froms {
+				from := time.Date(d[0], time.Month(d[1]), d[2], 0, 0, 0, 0, loc)
+				t.Run(fmt.Sprintf("%v: %v", cron, from), func(t *testing.T) {
+					it := expr.Iter(from, time.Time{})
+					next := from
+					for i := 0; i < 500; i++ {
+						next = expr.Next(next)
+						got, ok := it.Next()
+						require.Equal(t, !next.IsZero(), ok)
+						if !ok {
+							break
+						}
+						if !got.Equal(next) {
+							t.Fatalf("iteration %d: got %v, want %v", i, got, next)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestIter_Window(t *testing.T) {
+	expr := MustParse("0 */6 * * *")
+	from := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2021, 6, 2, 6, 0, 0, 0, time.UTC)
+
+	it := expr.Iter(from, until)
+	var times []time.Time
+	for next, ok := it.Next(); ok; next, ok = it.Next() {
+		times = append(times, next)
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2021, 6, 1, 6, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 1, 18, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 2, 6, 0, 0, 0, time.UTC),
+	}, times)
+
+	// Exhausted iterators keep returning false.
+	next, ok := it.Next()
+	assert.False(t, ok)
+	assert.True(t, next.IsZero())
+}
+
+func TestIter_End(t *testing.T) {
+	expr := MustParse("0 0 0 1 1 * 2098-2099")
+	it := expr.Iter(time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	for _, want := range []time.Time{
+		time.Date(2098, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		next, ok := it.Next()
+		require.True(t, ok)
+		assert.Equal(t, want, next)
+	}
+	_, ok := it.Next()
+	assert.False(t, ok)
+}
+
+func TestIter_Zero(t *testing.T) {
+	_, ok := MustParse("* * * * *").Iter(time.Time{}, time.Time{}).Next()
+	assert.False(t, ok)
+}
+
+func TestBetween(t *testing.T) {
+	expr := MustParse("30 9 * * 1-5")
+	from := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC) // Monday
+	until := time.Date(2021, 6, 11, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "no limit", limit: 0, want: 5},
+		{name: "negative limit", limit: -1, want: 5},
+		{name: "limit", limit: 3, want: 3},
+		{name: "limit above count", limit: 10, want: 5},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			times := expr.Between(from, until, tt.limit)
+			require.Len(t, times, tt.want)
+			for i, next := range times {
+				assert.Equal(t, time.Date(2021, 6, 7+i, 9, 30, 0, 0, time.UTC), next)
+			}
+		})
+	}
+
+	assert.Empty(t, expr.Between(until, from, 0))
+
+	// Without an end to the time window, a limit is required.
+	assert.Len(t, MustParse("* * * * * * *").Between(from, time.Time{}, 3), 3)
+	assert.Nil(t, MustParse("* * * * * * *").Between(from, time.Time{}, 0))
+	assert.Nil(t, MustParse("* * * * * * *").Between(from, time.Time{}, -1))
+}
+
+func BenchmarkIter(b *testing.B) {
+	exprs := make([]*Expression, benchmarkExpressionsLen)
+	for i := 0; i < benchmarkExpressionsLen; i++ {
+		exprs[i] = MustParse(benchmarkExpressions[i])
+	}
+	from := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := exprs[i%benchmarkExpressionsLen].Iter(from, time.Time{})
+		it.Next()
+		it.Next()
+		it.Next()
+		it.Next()
+		it.Next()
+	}
+}
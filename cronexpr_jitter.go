@@ -0,0 +1,81 @@
+package cronexpr
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithJitter returns a ParseOption that shifts each time instant of the
+// Expression forward by an offset from zero up to `max`, rounded down to whole
+// seconds, to spread the load of jobs which run at the same time instants.
+//
+// Unlike `H`, which picks the same value of a field for all time instants, the
+// offset is picked for each time instant, by hashing `seed`, such as the hash
+// ID of the job, together with the time instant. It is thus the same for the
+// same seed, across processes. A time instant is never shifted up to the time
+// instant following it, such that Next, Prev and Iter keep the same order.
+//
+// Jitter is not part of the Spec or String of an Expression, but it is
+// compared by Equal.
+func WithJitter(max time.Duration, seed string) ParseOption {
+	return &jitterParseOption{max: max, seed: seed}
+}
+
+type jitterParseOption struct {
+	*baseOption
+	max  time.Duration
+	seed string
+}
+
+func (o *jitterParseOption) Apply(expr *Expression) error {
+	if o.max < 0 {
+		return fmt.Errorf("invalid jitter: %v", o.max)
+	}
+	expr.jitter = o.max.Truncate(time.Second)
+	expr.jitterSeed = o.seed
+	return nil
+}
+
+// shift returns the time instant `t` matching the expression shifted by its
+// jitter, which is less than the time until the following time instant.
+func (expr *Expression) shift(t time.Time) time.Time {
+	limit := expr.jitter
+	if next := expr.nextOccurrence(t); !next.IsZero() && next.Sub(t) < limit {
+		limit = next.Sub(t)
+	}
+	seconds := uint64((limit + time.Second - 1) / time.Second)
+	if seconds == 0 {
+		return t
+	}
+	offset := HashString(expr.jitterSeed+t.UTC().Format(time.RFC3339Nano)) % seconds
+	return t.Add(time.Duration(offset) * time.Second)
+}
+
+// nextJittered returns the earliest shifted time instant following `fromTime`.
+func (expr *Expression) nextJittered(fromTime time.Time) time.Time {
+	// The time instant at or before `fromTime` may be shifted past it.
+	if t := expr.prevOccurrence(fromTime.Add(time.Nanosecond)); !t.IsZero() {
+		if shifted := expr.shift(t); shifted.After(fromTime) {
+			return shifted
+		}
+	}
+	t := expr.nextOccurrence(fromTime)
+	if t.IsZero() {
+		return t
+	}
+	return expr.shift(t)
+}
+
+// prevJittered returns the latest shifted time instant preceding `fromTime`.
+func (expr *Expression) prevJittered(fromTime time.Time) time.Time {
+	// The time instant before `fromTime` may be shifted up to it, but the one
+	// before that is shifted to before the former.
+	t := expr.prevOccurrence(fromTime)
+	for i := 0; i < 2 && !t.IsZero(); i++ {
+		if shifted := expr.shift(t); shifted.Before(fromTime) {
+			return shifted
+		}
+		t = expr.prevOccurrence(t)
+	}
+	return time.Time{}
+}
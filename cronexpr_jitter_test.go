@@ -0,0 +1,105 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJitter(t *testing.T) {
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		expr string
+		max  time.Duration
+		from time.Time
+	}{
+		{name: "within the interval", expr: "0 * * * *", max: 10 * time.Minute},
+		{name: "larger than the interval", expr: "*/5 * * * *", max: time.Hour},
+		{name: "irregular intervals", expr: "0,1,30 * * * *", max: 20 * time.Minute},
+		{name: "seconds", expr: "*/10 * * * * * *", max: time.Minute},
+		{name: "interval schedule", expr: "@every 90s", max: 2 * time.Minute},
+		{name: "time zone", expr: "CRON_TZ=America/Los_Angeles 0 * * * *", max: 30 * time.Minute},
+		{name: "last time instants", expr: "0 0 * * * * 2021", max: 5 * time.Hour, from: time.Date(2021, time.December, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			plain := MustParse(tt.expr)
+			expr, err := Parse(tt.expr, WithJitter(tt.max, "my-job"))
+			require.NoError(t, err)
+
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+			shifted := expr.NextN(start, 50)
+			require.True(t, len(shifted) > 2)
+
+			var offsets []time.Duration
+			for i, s := range shifted {
+				// Each time instant is shifted by up to `max`, and before the
+				// following time instant.
+				occurrence := plain.Prev(s.Add(time.Nanosecond))
+				offset := s.Sub(occurrence)
+				assert.True(t, offset >= 0 && offset <= tt.max, "offset %v of %v", offset, occurrence)
+				assert.Zero(t, offset%time.Second)
+				if following := plain.Next(occurrence); !following.IsZero() {
+					assert.True(t, s.Before(following), "%v shifted past %v", occurrence, following)
+				}
+				if i > 0 {
+					assert.Equal(t, occurrence, plain.Next(plain.Prev(shifted[i-1].Add(time.Nanosecond))))
+				}
+				offsets = append(offsets, offset)
+
+				// Next and Prev agree on the shifted time instants.
+				assert.Equal(t, s, expr.Next(s.Add(-time.Nanosecond)))
+				if i > 0 {
+					assert.Equal(t, shifted[i-1], expr.Prev(s))
+					assert.Equal(t, s, expr.Next(shifted[i-1]))
+				}
+			}
+			assert.NotEqual(t, offsets[0], offsets[len(offsets)-1], "offsets are not spread")
+			assert.Equal(t, shifted, expr.Between(start, shifted[len(shifted)-1], 0))
+			if len(shifted) < 50 {
+				// The last time instant is shifted too.
+				assert.Equal(t, plain.Prev(start.AddDate(1, 0, 0)), plain.Prev(shifted[len(shifted)-1].Add(time.Nanosecond)))
+			}
+
+			// The same seed gives the same time instants.
+			same := MustParse(tt.expr, WithJitter(tt.max, "my-job"))
+			assert.Equal(t, shifted, same.NextN(start, 50))
+			other := MustParse(tt.expr, WithJitter(tt.max, "other-job"))
+			assert.NotEqual(t, shifted, other.NextN(start, 50))
+		})
+	}
+}
+
+func TestWithJitter_BetweenOccurrences(t *testing.T) {
+	plain := MustParse("0 * * * *")
+	expr := MustParse("0 * * * *", WithJitter(30*time.Minute, "my-job"))
+	occurrence := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for expr.Next(occurrence.Add(-time.Nanosecond)).Equal(occurrence) {
+		occurrence = plain.Next(occurrence)
+	}
+
+	// The time instant is shifted past times matching the fields.
+	shifted := expr.Next(occurrence.Add(-time.Nanosecond))
+	assert.True(t, shifted.After(occurrence))
+	assert.Equal(t, shifted, expr.Next(occurrence))
+	assert.Equal(t, shifted, expr.Next(shifted.Add(-time.Second)))
+	assert.Equal(t, shifted, expr.Prev(shifted.Add(time.Second)))
+	assert.Equal(t, shifted, expr.Prev(plain.Next(occurrence)))
+}
+
+func TestWithJitter_Invalid(t *testing.T) {
+	_, err := Parse("0 * * * *", WithJitter(-time.Second, "my-job"))
+	assert.Error(t, err)
+
+	// Jitter under a second has no effect.
+	expr := MustParse("0 * * * *", WithJitter(time.Millisecond, "my-job"))
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, from.Add(time.Hour), expr.Next(from))
+}
@@ -0,0 +1,143 @@
+package cronexpr
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	kubernetesDowDescriptor = fieldDescriptor{
+		name:         "day-of-week",
+		min:          0,
+		max:          6,
+		hashmin:      0,
+		hashmax:      6,
+		defaultList:  genericDefaultList[0:7],
+		valuePattern: `0?[0-6]|sun|mon|tue|wed|thu|fri|sat|sunday|monday|tuesday|wednesday|thursday|friday|saturday`,
+		atoi: func(s string) int {
+			return dowTokens[s]
+		},
+	}
+
+	// Predefined aliases, as in github.com/robfig/cron.
+	kubernetesDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	kubernetesEveryFinder = regexp.MustCompile(`^\s*@every\s+(\S.*?)\s*$`)
+)
+
+// kubernetesFieldCount is the number of fields of CronFormatKubernetes, which has
+// neither seconds nor years.
+const kubernetesFieldCount = 5
+
+// kubernetesExpression implements custom parsing for the Kubernetes CronJob format.
+type kubernetesExpression struct {
+	*Expression
+}
+
+// lineHandler overrides the default cron line parsing. The cron line may be
+// preceded by `CRON_TZ=` or `TZ=` and a time zone name, and is either one of
+// the predefined aliases, `@every` and a time.Duration, or exactly 5 fields.
+func (expr *kubernetesExpression) lineHandler(cronLine string) (string, error) {
+	// `CRON_TZ=Asia/Tokyo`
+	cron, err := expr.timeZoneHandler(cronLine)
+	if err != nil {
+		return "", err
+	}
+
+	// `@every 1h30m`
+	if pairs := kubernetesEveryFinder.FindStringSubmatchIndex(cron); len(pairs) > 0 {
+		d, err := time.ParseDuration(cron[pairs[2]:pairs[3]])
+		if err != nil {
+			return "", newLineError(ReasonInvalidDuration, cron, pairs[2], pairs[3], "invalid interval: %v", err)
+		}
+		// As in github.com/robfig/cron, intervals are whole seconds of at least one second.
+		if d < time.Second {
+			d = time.Second
+		}
+		expr.every = d - d%time.Second
+		expr.everyRelative = true
+		return "", nil
+	}
+
+	// `@daily`
+	if descriptor, ok := kubernetesDescriptors[strings.TrimSpace(cron)]; ok {
+		return descriptor, nil
+	}
+
+	indices := fieldFinder.FindAllStringIndex(cron, -1)
+	if len(indices) > kubernetesFieldCount {
+		extra := indices[kubernetesFieldCount]
+		return "", newLineError(ReasonTooManyFields, cron, extra[0], extra[1],
+			"expected exactly %d fields, found %d", kubernetesFieldCount, len(indices))
+	}
+	return cron, nil
+}
+
+// domFieldHandler overrides the default day of month parsing, which does not
+// allow `L` and `W`.
+func (expr *kubernetesExpression) domFieldHandler(s string) error {
+	if err := kubernetesFieldCheck(s, domDescriptor, expr.hash); err != nil {
+		return err
+	}
+	if err := expr.Expression.domFieldHandler(s); err != nil {
+		return err
+	}
+	if isWildcardStepOne(s) {
+		expr.daysOfMonthRestricted = false
+	}
+	return nil
+}
+
+// dowFieldHandler overrides the default day of week parsing, which does not
+// allow `L`, `#` and 7 for Sunday.
+func (expr *kubernetesExpression) dowFieldHandler(s string) error {
+	if err := kubernetesFieldCheck(s, kubernetesDowDescriptor, expr.hash); err != nil {
+		return err
+	}
+	if err := expr.Expression.dowFieldHandler(s); err != nil {
+		return err
+	}
+	if isWildcardStepOne(s) {
+		expr.daysOfWeekRestricted = false
+	}
+	return nil
+}
+
+// kubernetesFieldCheck returns an error if a field has directives other than
+// values, ranges, intervals and hashes.
+func kubernetesFieldCheck(s string, desc fieldDescriptor, hash *hash) error {
+	directives, err := genericFieldParse(s, desc, hash)
+	if err != nil {
+		return err
+	}
+	for _, directive := range directives {
+		if directive.kind == none {
+			return newDirectiveError(ReasonUnknownToken, directive, "syntax error in %s field: '%s'", desc.name, s[directive.sbeg:directive.send])
+		}
+		if err := directive.IsValid(desc.min, desc.max); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isWildcardStepOne returns whether a field has a `*/1` directive, which, like
+// `*`, does not restrict days in github.com/robfig/cron, unlike other intervals.
+func isWildcardStepOne(s string) bool {
+	for _, entry := range entryFinder.FindAllString(s, -1) {
+		pairs := makeLayoutRegexp(layoutWildcardAndInterval, "").FindStringSubmatchIndex(entry)
+		if len(pairs) > 0 && atoi(entry[pairs[2]:pairs[3]]) == 1 {
+			return true
+		}
+	}
+	return false
+}
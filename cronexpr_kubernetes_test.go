@@ -0,0 +1,110 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesExpressions(t *testing.T) {
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []string
+	}{
+		{
+			name: "day of month or day of week",
+			expr: "0 0 13 * 5",
+			want: []string{"2021-03-05T00:00:00Z", "2021-03-12T00:00:00Z", "2021-03-13T00:00:00Z", "2021-03-19T00:00:00Z"},
+		},
+		{
+			name: "wildcard interval of one does not restrict days",
+			expr: "0 0 */1 * 1",
+			want: []string{"2021-03-08T00:00:00Z", "2021-03-15T00:00:00Z", "2021-03-22T00:00:00Z", "2021-03-29T00:00:00Z"},
+		},
+		{
+			name: "wildcard interval restricts days",
+			expr: "0 0 */2 * 1",
+			want: []string{"2021-03-03T00:00:00Z", "2021-03-05T00:00:00Z", "2021-03-07T00:00:00Z", "2021-03-08T00:00:00Z"},
+		},
+		{
+			name: "question mark",
+			expr: "30 6 ? * ?",
+			want: []string{"2021-03-01T06:30:00Z", "2021-03-02T06:30:00Z", "2021-03-03T06:30:00Z", "2021-03-04T06:30:00Z"},
+		},
+		{
+			name: "predefined alias",
+			expr: "@weekly",
+			want: []string{"2021-03-07T00:00:00Z", "2021-03-14T00:00:00Z", "2021-03-21T00:00:00Z", "2021-03-28T00:00:00Z"},
+		},
+		{
+			name: "interval",
+			expr: "@every 90m",
+			from: from.Add(500 * time.Millisecond),
+			want: []string{"2021-03-01T01:30:00Z", "2021-03-01T03:00:00Z", "2021-03-01T04:30:00Z", "2021-03-01T06:00:00Z"},
+		},
+		{
+			name: "interval below one second",
+			expr: "@every 100ms",
+			want: []string{"2021-03-01T00:00:01Z", "2021-03-01T00:00:02Z", "2021-03-01T00:00:03Z", "2021-03-01T00:00:04Z"},
+		},
+		{
+			name: "time zone",
+			expr: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			from: from.Add(30 * time.Minute),
+			want: []string{"2021-03-02T00:00:00Z", "2021-03-03T00:00:00Z", "2021-03-04T00:00:00Z", "2021-03-05T00:00:00Z"},
+		},
+		{
+			name: "time zone across daylight saving change",
+			expr: "TZ=America/New_York 0 9 * * 1-5",
+			from: time.Date(2021, time.March, 12, 15, 0, 0, 0, time.UTC),
+			want: []string{"2021-03-15T13:00:00Z", "2021-03-16T13:00:00Z", "2021-03-17T13:00:00Z", "2021-03-18T13:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseForFormat(CronFormatKubernetes, tt.expr)
+			require.NoError(t, err)
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+
+			nexts := expr.NextN(start, 4)
+			var got []string
+			for _, next := range nexts {
+				assert.Equal(t, time.UTC, next.Location())
+				got = append(got, next.Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.Equal(t, nexts, expr.Between(start, nexts[len(nexts)-1], 0))
+			assert.Equal(t, nexts[1], expr.Prev(nexts[2]))
+		})
+	}
+}
+
+func TestKubernetesExpressions_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 * * * *",
+		"0 0 0 * * * 2021",
+		"0 0 * * 7",
+		"0 0 * * 5L",
+		"0 0 * * 5#3",
+		"0 0 LW * *",
+		"0 0 15W * *",
+		"@reboot",
+		"@every",
+		"@every 1 hour",
+		"CRON_TZ=Unknown/Zone 0 0 * * *",
+		"CRON_TZ=Asia/Tokyo",
+	} {
+		_, err := ParseForFormat(CronFormatKubernetes, expr)
+		assert.Error(t, err, "ParseForFormat(%q)", expr)
+	}
+}
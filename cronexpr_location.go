@@ -0,0 +1,58 @@
+package cronexpr
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var timeZoneFinder = regexp.MustCompile(`^\s*((?:CRON_)?TZ=)(\S*)`)
+
+// WithLocation returns a ParseOption that sets the time zone in which the fields of
+// a cron expression are matched, as with a `CRON_TZ=` prefix. The time instants
+// returned by Next and Prev are still in the time zone of the given time.
+//
+// A `CRON_TZ=` or `TZ=` prefix of the cron line takes precedence over this option.
+// To be serialized with Spec, `loc` should be loaded by name with time.LoadLocation.
+func WithLocation(loc *time.Location) ParseOption {
+	return &locationParseOption{loc: loc}
+}
+
+type locationParseOption struct {
+	*baseOption
+	loc *time.Location
+}
+
+func (o *locationParseOption) Apply(expr *Expression) error {
+	expr.location = o.loc
+	return nil
+}
+
+// Location returns the time zone in which the fields of the Expression are
+// matched, or nil if they are matched in the time zone of the given time.
+func (expr *Expression) Location() *time.Location {
+	return expr.location
+}
+
+// timeZoneHandler parses the `CRON_TZ=` or `TZ=` prefix of the cron line, if
+// any, and returns the cron line with the prefix blanked out, so that errors
+// are still located within the original cron line.
+//
+// The time zone of the prefix takes precedence over WithLocation.
+func (expr *Expression) timeZoneHandler(cronLine string) (string, error) {
+	pairs := timeZoneFinder.FindStringSubmatchIndex(cronLine)
+	if len(pairs) == 0 {
+		return cronLine, nil
+	}
+	name := cronLine[pairs[4]:pairs[5]]
+	// time.LoadLocation loads UTC for an empty name.
+	if name == "" {
+		return "", newLineError(ReasonUnknownTimeZone, cronLine, pairs[2], pairs[3], "missing time zone")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", newLineError(ReasonUnknownTimeZone, cronLine, pairs[4], pairs[5], "unknown time zone %s: %v", name, err)
+	}
+	expr.location = loc
+	return strings.Repeat(" ", pairs[1]) + cronLine[pairs[1]:], nil
+}
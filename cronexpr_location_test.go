@@ -0,0 +1,148 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocation(t *testing.T) {
+	from := time.Date(2021, time.March, 1, 1, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		from    time.Time
+		want    []string
+	}{
+		{
+			name: "time zone prefix",
+			expr: "CRON_TZ=Asia/Singapore 0 9 * * *",
+			want: []string{"2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z", "2021-03-04T01:00:00Z"},
+		},
+		{
+			name: "TZ prefix",
+			expr: "TZ=Asia/Singapore 0 9 * * *",
+			want: []string{"2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z", "2021-03-04T01:00:00Z"},
+		},
+		{
+			name:   "time zone prefix with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "CRON_TZ=Asia/Singapore 0 0 9 ? * MON-FRI *",
+			want:   []string{"2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z", "2021-03-04T01:00:00Z"},
+		},
+		{
+			name:    "location",
+			expr:    "0 9 * * *",
+			options: []ParseOption{WithLocation(mustLoadLocation("Asia/Singapore"))},
+			want:    []string{"2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z", "2021-03-04T01:00:00Z"},
+		},
+		{
+			name:    "time zone prefix takes precedence over location",
+			expr:    "CRON_TZ=Asia/Singapore 0 9 * * *",
+			options: []ParseOption{WithLocation(mustLoadLocation("America/New_York"))},
+			want:    []string{"2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z", "2021-03-04T01:00:00Z"},
+		},
+		{
+			name: "results in the time zone of the given time",
+			expr: "CRON_TZ=Asia/Singapore 0 9 * * *",
+			from: time.Date(2021, time.March, 1, 0, 0, 0, 0, mustLoadLocation("Europe/London")),
+			want: []string{"2021-03-01T01:00:00Z", "2021-03-02T01:00:00Z", "2021-03-03T01:00:00Z"},
+		},
+		{
+			name: "day of month in the time zone",
+			expr: "CRON_TZ=Pacific/Auckland 0 8 1 * *",
+			want: []string{"2021-03-31T19:00:00Z", "2021-04-30T20:00:00Z", "2021-05-31T20:00:00Z"},
+		},
+		{
+			name: "spring forward",
+			expr: "CRON_TZ=America/New_York 30 2 * * *",
+			from: time.Date(2021, time.March, 13, 0, 0, 0, 0, time.UTC),
+			want: []string{"2021-03-13T07:30:00Z", "2021-03-15T06:30:00Z", "2021-03-16T06:30:00Z"},
+		},
+		{
+			name: "fall back",
+			expr: "CRON_TZ=America/New_York 30 1 * * *",
+			from: time.Date(2021, time.November, 6, 0, 0, 0, 0, time.UTC),
+			// 1:30 happens twice on November 7.
+			want: []string{"2021-11-06T05:30:00Z", "2021-11-07T05:30:00Z", "2021-11-07T06:30:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			expr, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.NoError(t, err)
+			require.NotNil(t, expr.Location())
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+
+			nexts := expr.NextN(start, 3)
+			var got []string
+			for _, next := range nexts {
+				assert.Equal(t, start.Location(), next.Location())
+				got = append(got, next.UTC().Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.Equal(t, []time.Time{nexts[1], nexts[0]}, expr.PrevN(nexts[2], 2))
+			assert.Equal(t, nexts, expr.Between(start, nexts[2], 0))
+
+			// The normalized form keeps the time zone.
+			normalized, err := ParseForFormat(format, expr.String(), tt.options...)
+			require.NoError(t, err)
+			assert.Equal(t, nexts, normalized.NextN(start, 3))
+		})
+	}
+}
+
+// TestLocation_MatchesGivenTimeZone checks that matching the fields in an
+// embedded time zone gives the same time instants as giving times in that
+// time zone, including across daylight saving time changes.
+func TestLocation_MatchesGivenTimeZone(t *testing.T) {
+	loc := mustLoadLocation("America/Los_Angeles")
+	for _, cron := range []string{"0 2 * * *", "1 2 * * *", "0 1 * * *", "30 1 * * *", "*/20 * * * *", "0 0 L * *"} {
+		expr := MustParse(cron)
+		inLoc := MustParse(cron, WithLocation(loc))
+		for _, from := range []time.Time{
+			time.Date(2019, time.March, 9, 0, 0, 0, 0, loc),
+			time.Date(2019, time.November, 2, 0, 0, 0, 0, loc),
+		} {
+			want := expr.NextN(from, 100)
+			got := inLoc.NextN(from.UTC(), 100)
+			require.Len(t, got, len(want), cron)
+			for i := range want {
+				assert.True(t, want[i].Equal(got[i]), "%s: Next #%d from %v: want %v, got %v", cron, i, from, want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestLocation_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"CRON_TZ=Unknown/Zone 0 0 * * *",
+		"CRON_TZ=Asia/Tokyo",
+		"CRON_TZ= 0 0 * * *",
+		"TZ= 0 0 * * *",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, "Parse(%q)", expr)
+	}
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
@@ -0,0 +1,111 @@
+package cronexpr
+
+import (
+	"fmt"
+	"time"
+)
+
+// MissedRunPolicy sets which of the time instants missed while a job was not
+// running, e.g. while its controller was down, should be run to catch up. The
+// zero value runs the latest missed time instant only.
+type MissedRunPolicy struct {
+	// Mode sets which of the missed time instants are run.
+	Mode MissedRunMode `json:"mode,omitempty"`
+
+	// Limit is the maximum number of time instants run with MissedRunAll,
+	// which then runs the latest ones. Zero means no limit.
+	Limit int `json:"limit,omitempty"`
+
+	// StartingDeadline, if not zero, is how late a time instant can be run.
+	// Missed time instants before `now` minus StartingDeadline are skipped,
+	// as with the `startingDeadlineSeconds` of a Kubernetes CronJob.
+	StartingDeadline time.Duration `json:"startingDeadline,omitempty"`
+}
+
+// MissedRunMode is an enum for which of the missed time instants are run.
+type MissedRunMode string
+
+const (
+	// All of the missed time instants are run, up to the Limit of the policy.
+	MissedRunAll MissedRunMode = "all"
+
+	// Only the latest missed time instant is run. This is the default, and,
+	// with a StartingDeadline, what a Kubernetes CronJob does.
+	MissedRunLatest MissedRunMode = "latest"
+
+	// None of the missed time instants are run.
+	MissedRunSkip MissedRunMode = "skip"
+)
+
+// MissedRuns returns the time instants matching the expression which were
+// missed from `lastRun` up to and including `now`, and should be run as per
+// `policy`, in chronological order. A zero `lastRun` means that the job has
+// not run yet, such that only the StartingDeadline of the policy bounds how
+// far back the time instants go.
+//
+// The time instants are found with Prev, going back from `now`, rather than by
+// going through all of the missed time instants, unless all of them are run.
+// The `time.Location` of the returned time instants is the same as that of
+// `now`.
+func (expr *Expression) MissedRuns(lastRun, now time.Time, policy MissedRunPolicy) ([]time.Time, error) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	// Time instants are missed if they follow `since`.
+	since := lastRun
+	if policy.StartingDeadline > 0 {
+		if earliest := now.Add(-policy.StartingDeadline); since.IsZero() || earliest.After(since) {
+			since = earliest
+		}
+	}
+	if now.IsZero() || (!since.IsZero() && !now.After(since)) {
+		return nil, nil
+	}
+	// Prev returns time instants preceding its argument, and `now` is itself
+	// missed if it matches.
+	until := now.Add(time.Nanosecond)
+
+	switch policy.Mode {
+	case MissedRunSkip:
+		return nil, nil
+
+	case MissedRunAll:
+		if policy.Limit == 0 {
+			if since.IsZero() {
+				return nil, fmt.Errorf("cannot run all missed time instants without a last run, a limit or a starting deadline")
+			}
+			return expr.Between(since.In(now.Location()), now, 0), nil
+		}
+		var runs []time.Time
+		for prev := expr.Prev(until); len(runs) < policy.Limit && prev.After(since); prev = expr.Prev(prev) {
+			runs = append(runs, prev)
+		}
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+		return runs, nil
+
+	default:
+		prev := expr.Prev(until)
+		if prev.IsZero() || !prev.After(since) {
+			return nil, nil
+		}
+		return []time.Time{prev}, nil
+	}
+}
+
+func (policy MissedRunPolicy) validate() error {
+	switch policy.Mode {
+	case "", MissedRunAll, MissedRunLatest, MissedRunSkip:
+	default:
+		return fmt.Errorf("invalid missed run mode: %q", policy.Mode)
+	}
+	if policy.Limit < 0 {
+		return fmt.Errorf("invalid missed run limit: %d", policy.Limit)
+	}
+	if policy.StartingDeadline < 0 {
+		return fmt.Errorf("invalid starting deadline: %v", policy.StartingDeadline)
+	}
+	return nil
+}
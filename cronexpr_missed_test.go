@@ -0,0 +1,143 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedRuns(t *testing.T) {
+	lastRun := time.Date(2021, time.March, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2021, time.March, 1, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expr      string
+		lastRun   time.Time
+		noLastRun bool
+		now       time.Time
+		policy    MissedRunPolicy
+		want      []string
+	}{
+		{
+			name:   "all",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunAll},
+			want:   []string{"10:00:00", "11:00:00", "12:00:00", "13:00:00", "14:00:00"},
+		},
+		{
+			name:   "all up to a limit",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunAll, Limit: 2},
+			want:   []string{"13:00:00", "14:00:00"},
+		},
+		{
+			name:   "all within a limit",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunAll, Limit: 100},
+			want:   []string{"10:00:00", "11:00:00", "12:00:00", "13:00:00", "14:00:00"},
+		},
+		{
+			name:   "all within a starting deadline",
+			expr:   "*/30 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunAll, StartingDeadline: time.Hour},
+			want:   []string{"14:00:00", "14:30:00"},
+		},
+		{
+			name:      "all without a last run",
+			expr:      "0 * * * *",
+			noLastRun: true,
+			policy:    MissedRunPolicy{Mode: MissedRunAll, Limit: 3},
+			want:      []string{"12:00:00", "13:00:00", "14:00:00"},
+		},
+		{
+			name:   "latest",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunLatest},
+			want:   []string{"14:00:00"},
+		},
+		{
+			name:   "latest by default",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{},
+			want:   []string{"14:00:00"},
+		},
+		{
+			name:   "latest at the current time",
+			expr:   "30 14 * * *",
+			policy: MissedRunPolicy{},
+			want:   []string{"14:30:00"},
+		},
+		{
+			name:   "latest within a starting deadline",
+			expr:   "0 12 * * *",
+			policy: MissedRunPolicy{StartingDeadline: 3 * time.Hour},
+			want:   []string{"12:00:00"},
+		},
+		{
+			name:   "latest past a starting deadline",
+			expr:   "0 12 * * *",
+			policy: MissedRunPolicy{StartingDeadline: 2 * time.Hour},
+		},
+		{
+			name:   "latest following the last run only",
+			expr:   "0 9 * * *",
+			policy: MissedRunPolicy{Mode: MissedRunLatest},
+		},
+		{
+			name:   "skip",
+			expr:   "0 * * * *",
+			policy: MissedRunPolicy{Mode: MissedRunSkip},
+		},
+		{
+			name:   "current time before the last run",
+			expr:   "0 * * * *",
+			now:    lastRun.Add(-time.Hour),
+			policy: MissedRunPolicy{Mode: MissedRunAll},
+		},
+		{
+			name:    "large gap",
+			expr:    "* * * * * * *",
+			lastRun: time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC),
+			policy:  MissedRunPolicy{Mode: MissedRunAll, Limit: 2},
+			want:    []string{"14:29:59", "14:30:00"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			last, current := tt.lastRun, tt.now
+			if last.IsZero() && !tt.noLastRun {
+				last = lastRun
+			}
+			if current.IsZero() {
+				current = now
+			}
+			runs, err := MustParse(tt.expr).MissedRuns(last, current, tt.policy)
+			require.NoError(t, err)
+			var got []string
+			for _, run := range runs {
+				got = append(got, run.Format("15:04:05"))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMissedRuns_Invalid(t *testing.T) {
+	expr := MustParse("0 * * * *")
+	now := time.Date(2021, time.March, 1, 14, 30, 0, 0, time.UTC)
+	for _, policy := range []MissedRunPolicy{
+		{Mode: "some"},
+		{Mode: MissedRunAll, Limit: -1},
+		{StartingDeadline: -time.Second},
+	} {
+		_, err := expr.MissedRuns(now.Add(-time.Hour), now, policy)
+		assert.Error(t, err, policy)
+	}
+
+	// All of the time instants since the beginning of time cannot be run.
+	_, err := expr.MissedRuns(time.Time{}, now, MissedRunPolicy{Mode: MissedRunAll})
+	assert.Error(t, err)
+}
@@ -18,111 +18,140 @@ package cronexpr
 
 import (
 	"sort"
+	"sync/atomic"
 	"time"
 )
 
 /******************************************************************************/
 
-var dowNormalizedOffsets = [][]int{
-	{1, 8, 15, 22, 29},
-	{2, 9, 16, 23, 30},
-	{3, 10, 17, 24, 31},
-	{4, 11, 18, 25},
-	{5, 12, 19, 26},
-	{6, 13, 20, 27},
-	{7, 14, 21, 28},
-}
+// dayMaskCacheSize is the number of months whose matching days are cached
+// by an Expression.
+const dayMaskCacheSize = 16
+
+// dayMaskCache caches the matching days of recently evaluated months. Each
+// entry packs a month, counted from January of year 0, in its upper 32 bits,
+// and the bitset of matching days of that month in its lower 32 bits, so that
+// entries can be read and written atomically by concurrent evaluations.
+type dayMaskCache [dayMaskCacheSize]uint64
+
+// everyWeek has a bit set every 7 days over 5 weeks.
+const everyWeek bitset = 1<<0 | 1<<7 | 1<<14 | 1<<21 | 1<<28
 
 /******************************************************************************/
 
-func (expr *Expression) calculateActualDaysOfMonth(year, month int) []int {
-	actualDaysOfMonthMap := make(map[int]bool)
-	firstDayOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1)
+// daysOfMonthMask returns the days of the given month which match the
+// day-of-month and day-of-week fields of the expression.
+func (expr *Expression) daysOfMonthMask(year int, month time.Month) bitset {
+	if expr.dayMasks == nil || year < 0 {
+		return expr.calculateDaysOfMonth(year, month)
+	}
+
+	key := uint64(year)*12 + uint64(month)
+	entry := &expr.dayMasks[key%dayMaskCacheSize]
+	if v := atomic.LoadUint64(entry); v>>32 == key {
+		return bitset(uint32(v))
+	}
+	days := expr.calculateDaysOfMonth(year, month)
+	atomic.StoreUint64(entry, key<<32|uint64(days))
+	return days
+}
+
+func (expr *Expression) calculateDaysOfMonth(year int, month time.Month) bitset {
+	firstDayOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1).Day()
+	firstWeekday := int(firstDayOfMonth.Weekday())
+	allDays := bitset(1<<uint(lastDayOfMonth+1) - 2)
 
 	// As per crontab man page (http://linux.die.net/man/5/crontab#):
 	//  "The day of a command's execution can be specified by two
 	//  "fields - day of month, and day of week. If both fields are
 	//  "restricted (ie, aren't *), the command will be run when
 	//  "either field matches the current time"
+	// With DayMatchAnd, both fields have to match instead.
 
 	// If both fields are not restricted, all days of the month are a hit
-	if expr.daysOfMonthRestricted == false && expr.daysOfWeekRestricted == false {
-		return genericDefaultList[1 : lastDayOfMonth.Day()+1]
+	if !expr.daysOfMonthRestricted && !expr.daysOfWeekRestricted {
+		return allDays
 	}
 
+	var days, daysOfWeek bitset
+
 	// day-of-month != `*`
 	if expr.daysOfMonthRestricted {
 		// Last day of month
 		if expr.lastDayOfMonth {
-			actualDaysOfMonthMap[lastDayOfMonth.Day()] = true
+			days.add(lastDayOfMonth)
+		}
+		// Days before last day of month
+		for offsets := expr.daysBeforeLastDayOfMonth; offsets != 0; {
+			v := offsets.first()
+			offsets &^= 1 << uint(v)
+			if v < lastDayOfMonth {
+				days.add(lastDayOfMonth - v)
+			}
 		}
 		// Last work day of month
 		if expr.lastWorkdayOfMonth {
-			actualDaysOfMonthMap[workdayOfMonth(lastDayOfMonth, lastDayOfMonth)] = true
+			days.add(workdayOfMonth(lastDayOfMonth, firstWeekday, lastDayOfMonth))
 		}
-		// Days of month
-		for v := range expr.daysOfMonth {
-			// Ignore days beyond end of month
-			if v <= lastDayOfMonth.Day() {
-				actualDaysOfMonthMap[v] = true
+		// Work days nearest to days before last day of month
+		for offsets := expr.workdaysBeforeLastDayOfMonth; offsets != 0; {
+			v := offsets.first()
+			offsets &^= 1 << uint(v)
+			if v < lastDayOfMonth {
+				days.add(workdayOfMonth(lastDayOfMonth-v, firstWeekday, lastDayOfMonth))
 			}
 		}
+		// Days of month, ignoring days beyond end of month
+		days |= expr.daysOfMonth & allDays
 		// Work days of month
 		// As per Wikipedia: month boundaries are not crossed.
-		for v := range expr.workdaysOfMonth {
-			// Ignore days beyond end of month
-			if v <= lastDayOfMonth.Day() {
-				actualDaysOfMonthMap[workdayOfMonth(firstDayOfMonth.AddDate(0, 0, v-1), lastDayOfMonth)] = true
-			}
+		for workdays := expr.workdaysOfMonth & allDays; workdays != 0; {
+			v := workdays.first()
+			workdays &^= 1 << uint(v)
+			days.add(workdayOfMonth(v, firstWeekday, lastDayOfMonth))
 		}
 	}
 
 	// day-of-week != `*`
 	if expr.daysOfWeekRestricted {
-		// How far first sunday is from first day of month
-		offset := 7 - int(firstDayOfMonth.Weekday())
-		// days of week
-		//  offset : (7 - day_of_week_of_1st_day_of_month)
-		//  target : 1 + (7 * week_of_month) + (offset + day_of_week) % 7
-		for v := range expr.daysOfWeek {
-			w := dowNormalizedOffsets[(offset+v)%7]
-			actualDaysOfMonthMap[w[0]] = true
-			actualDaysOfMonthMap[w[1]] = true
-			actualDaysOfMonthMap[w[2]] = true
-			actualDaysOfMonthMap[w[3]] = true
-			if len(w) > 4 && w[4] <= lastDayOfMonth.Day() {
-				actualDaysOfMonthMap[w[4]] = true
-			}
+		// days of week, every 7 days from their first occurrence in the month
+		for dows := expr.daysOfWeek; dows != 0; {
+			v := dows.first()
+			dows &^= 1 << uint(v)
+			daysOfWeek |= everyWeek << uint(1+(v-firstWeekday+7)%7)
 		}
 		// days of week of specific week in the month
-		//  offset : (7 - day_of_week_of_1st_day_of_month)
-		//  target : 1 + (7 * week_of_month) + (offset + day_of_week) % 7
-		for v := range expr.specificWeekDaysOfWeek {
-			v = 1 + 7*(v/7) + (offset+v)%7
-			if v <= lastDayOfMonth.Day() {
-				actualDaysOfMonthMap[v] = true
-			}
+		for specific := expr.specificWeekDaysOfWeek; specific != 0; {
+			v := specific.first()
+			specific &^= 1 << uint(v)
+			daysOfWeek.add(1 + 7*(v/7) + (v%7-firstWeekday+7)%7)
 		}
 		// Last days of week of the month
-		lastWeekOrigin := firstDayOfMonth.AddDate(0, 1, -7)
-		offset = 7 - int(lastWeekOrigin.Weekday())
-		for v := range expr.lastWeekDaysOfWeek {
-			v = lastWeekOrigin.Day() + (offset+v)%7
-			if v <= lastDayOfMonth.Day() {
-				actualDaysOfMonthMap[v] = true
-			}
+		lastWeekOrigin := lastDayOfMonth - 6
+		lastWeekOriginWeekday := (firstWeekday + lastWeekOrigin - 1) % 7
+		for last := expr.lastWeekDaysOfWeek; last != 0; {
+			v := last.first()
+			last &^= 1 << uint(v)
+			daysOfWeek.add(lastWeekOrigin + (v-lastWeekOriginWeekday+7)%7)
 		}
 	}
 
-	return toList(actualDaysOfMonthMap)
+	if expr.daysOfMonthRestricted && expr.daysOfWeekRestricted && expr.dayMatchMode == DayMatchAnd {
+		days &= daysOfWeek
+	} else {
+		days |= daysOfWeek
+	}
+
+	return days & allDays
 }
 
-func workdayOfMonth(targetDom, lastDom time.Time) int {
+// workdayOfMonth returns the work day nearest to the day `dom` of a month,
+// given the day of week of the first day of the month and its last day.
+func workdayOfMonth(dom, firstWeekday, lastDom int) int {
 	// If saturday, then friday
 	// If sunday, then monday
-	dom := targetDom.Day()
-	dow := targetDom.Weekday()
+	dow := time.Weekday((firstWeekday + dom - 1) % 7)
 	if dow == time.Saturday {
 		if dom > 1 {
 			dom -= 1
@@ -130,7 +159,7 @@ func workdayOfMonth(targetDom, lastDom time.Time) int {
 			dom += 2
 		}
 	} else if dow == time.Sunday {
-		if dom < lastDom.Day() {
+		if dom < lastDom {
 			dom += 1
 		} else {
 			dom -= 2
@@ -139,9 +168,77 @@ func workdayOfMonth(targetDom, lastDom time.Time) int {
 	return dom
 }
 
-func sortContains(a []int, x int) bool {
+// nextYear returns the earliest year of the expression which is not before
+// `year`, and false if there is none.
+func (expr *Expression) nextYear(year int) (int, bool) {
+	if expr.yearList == nil {
+		if year < yearDescriptor.min {
+			return yearDescriptor.min, true
+		}
+		return year, year <= yearDescriptor.max
+	}
+	i := sort.SearchInts(expr.yearList, year)
+	if i == len(expr.yearList) {
+		return 0, false
+	}
+	return expr.yearList[i], true
+}
+
+// prevYear returns the latest year of the expression which is not after
+// `year`, and false if there is none.
+func (expr *Expression) prevYear(year int) (int, bool) {
+	if expr.yearList == nil {
+		if year > yearDescriptor.max {
+			return yearDescriptor.max, true
+		}
+		return year, year >= yearDescriptor.min
+	}
+	i := searchIntsBefore(expr.yearList, year)
+	if i < 0 {
+		return 0, false
+	}
+	return expr.yearList[i], true
+}
+
+// searchIntsBefore returns the index of the largest value in the sorted slice
+// `a` which is less than or equal to `x`, or -1 if there is none.
+func searchIntsBefore(a []int, x int) int {
 	i := sort.SearchInts(a, x)
-	return i < len(a) && a[i] == x
+	if i < len(a) && a[i] == x {
+		return i
+	}
+	return i - 1
+}
+
+// startOfHour returns the earliest instant of the wall clock hour `t` is in.
+// If the UTC offset changed during that hour, the hour started at the
+// transition rather than at minute 0.
+func startOfHour(t time.Time) time.Time {
+	start := t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+	_, off := t.Zone()
+	if _, startOff := start.Zone(); startOff == off {
+		return start
+	}
+
+	// Search for the first second having the same offset as `t`.
+	lo, hi := start, t
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if _, midOff := mid.Zone(); midOff == off {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// inLocation returns `t` in the time zone `loc`, unless it is the zero value.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(loc)
 }
 
 func timeZoneInDay(t time.Time) bool {
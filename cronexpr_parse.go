@@ -17,9 +17,9 @@ package cronexpr
 /******************************************************************************/
 
 import (
-	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -35,48 +35,11 @@ var (
 		40, 41, 42, 43, 44, 45, 46, 47, 48, 49,
 		50, 51, 52, 53, 54, 55, 56, 57, 58, 59,
 	}
-	yearDefaultList = []int{
-		1970, 1971, 1972, 1973, 1974, 1975, 1976, 1977, 1978, 1979,
-		1980, 1981, 1982, 1983, 1984, 1985, 1986, 1987, 1988, 1989,
-		1990, 1991, 1992, 1993, 1994, 1995, 1996, 1997, 1998, 1999,
-		2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008, 2009,
-		2010, 2011, 2012, 2013, 2014, 2015, 2016, 2017, 2018, 2019,
-		2020, 2021, 2022, 2023, 2024, 2025, 2026, 2027, 2028, 2029,
-		2030, 2031, 2032, 2033, 2034, 2035, 2036, 2037, 2038, 2039,
-		2040, 2041, 2042, 2043, 2044, 2045, 2046, 2047, 2048, 2049,
-		2050, 2051, 2052, 2053, 2054, 2055, 2056, 2057, 2058, 2059,
-		2060, 2061, 2062, 2063, 2064, 2065, 2066, 2067, 2068, 2069,
-		2070, 2071, 2072, 2073, 2074, 2075, 2076, 2077, 2078, 2079,
-		2080, 2081, 2082, 2083, 2084, 2085, 2086, 2087, 2088, 2089,
-		2090, 2091, 2092, 2093, 2094, 2095, 2096, 2097, 2098, 2099,
-	}
 )
 
 /******************************************************************************/
 
 var (
-	numberTokens = map[string]int{
-		"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
-		"00": 0, "01": 1, "02": 2, "03": 3, "04": 4, "05": 5, "06": 6, "07": 7, "08": 8, "09": 9,
-		"10": 10, "11": 11, "12": 12, "13": 13, "14": 14, "15": 15, "16": 16, "17": 17, "18": 18, "19": 19,
-		"20": 20, "21": 21, "22": 22, "23": 23, "24": 24, "25": 25, "26": 26, "27": 27, "28": 28, "29": 29,
-		"30": 30, "31": 31, "32": 32, "33": 33, "34": 34, "35": 35, "36": 36, "37": 37, "38": 38, "39": 39,
-		"40": 40, "41": 41, "42": 42, "43": 43, "44": 44, "45": 45, "46": 46, "47": 47, "48": 48, "49": 49,
-		"50": 50, "51": 51, "52": 52, "53": 53, "54": 54, "55": 55, "56": 56, "57": 57, "58": 58, "59": 59,
-		"1970": 1970, "1971": 1971, "1972": 1972, "1973": 1973, "1974": 1974, "1975": 1975, "1976": 1976, "1977": 1977, "1978": 1978, "1979": 1979,
-		"1980": 1980, "1981": 1981, "1982": 1982, "1983": 1983, "1984": 1984, "1985": 1985, "1986": 1986, "1987": 1987, "1988": 1988, "1989": 1989,
-		"1990": 1990, "1991": 1991, "1992": 1992, "1993": 1993, "1994": 1994, "1995": 1995, "1996": 1996, "1997": 1997, "1998": 1998, "1999": 1999,
-		"2000": 2000, "2001": 2001, "2002": 2002, "2003": 2003, "2004": 2004, "2005": 2005, "2006": 2006, "2007": 2007, "2008": 2008, "2009": 2009,
-		"2010": 2010, "2011": 2011, "2012": 2012, "2013": 2013, "2014": 2014, "2015": 2015, "2016": 2016, "2017": 2017, "2018": 2018, "2019": 2019,
-		"2020": 2020, "2021": 2021, "2022": 2022, "2023": 2023, "2024": 2024, "2025": 2025, "2026": 2026, "2027": 2027, "2028": 2028, "2029": 2029,
-		"2030": 2030, "2031": 2031, "2032": 2032, "2033": 2033, "2034": 2034, "2035": 2035, "2036": 2036, "2037": 2037, "2038": 2038, "2039": 2039,
-		"2040": 2040, "2041": 2041, "2042": 2042, "2043": 2043, "2044": 2044, "2045": 2045, "2046": 2046, "2047": 2047, "2048": 2048, "2049": 2049,
-		"2050": 2050, "2051": 2051, "2052": 2052, "2053": 2053, "2054": 2054, "2055": 2055, "2056": 2056, "2057": 2057, "2058": 2058, "2059": 2059,
-		"2060": 2060, "2061": 2061, "2062": 2062, "2063": 2063, "2064": 2064, "2065": 2065, "2066": 2066, "2067": 2067, "2068": 2068, "2069": 2069,
-		"2070": 2070, "2071": 2071, "2072": 2072, "2073": 2073, "2074": 2074, "2075": 2075, "2076": 2076, "2077": 2077, "2078": 2078, "2079": 2079,
-		"2080": 2080, "2081": 2081, "2082": 2082, "2083": 2083, "2084": 2084, "2085": 2085, "2086": 2086, "2087": 2087, "2088": 2088, "2089": 2089,
-		"2090": 2090, "2091": 2091, "2092": 2092, "2093": 2093, "2094": 2094, "2095": 2095, "2096": 2096, "2097": 2097, "2098": 2098, "2099": 2099,
-	}
 	monthTokens = map[string]int{
 		`1`: 1, `01`: 1, `jan`: 1, `january`: 1,
 		`2`: 2, `02`: 2, `feb`: 2, `february`: 2,
@@ -105,24 +68,36 @@ var (
 
 /******************************************************************************/
 
+// atoi returns the value of a number matched by one of the layouts, or 0 if
+// it is out of the range of int.
 func atoi(s string) int {
-	return numberTokens[s]
+	v, _ := strconv.Atoi(s)
+	return v
 }
 
 type fieldDescriptor struct {
 	name         string
 	min, max     int
+	hashmin      int
 	hashmax      int
 	defaultList  []int
 	valuePattern string
 	atoi         func(string) int
 }
 
+// all returns the bitset of all values of the field.
+func (desc fieldDescriptor) all() bitset {
+	var b bitset
+	b.addRange(desc.min, desc.max, 1)
+	return b
+}
+
 var (
 	secondDescriptor = fieldDescriptor{
 		name:         "second",
 		min:          0,
 		max:          59,
+		hashmin:      0,
 		hashmax:      59,
 		defaultList:  genericDefaultList[0:60],
 		valuePattern: `0?[0-9]|[1-5][0-9]`,
@@ -132,6 +107,7 @@ var (
 		name:         "minute",
 		min:          0,
 		max:          59,
+		hashmin:      0,
 		hashmax:      59,
 		defaultList:  genericDefaultList[0:60],
 		valuePattern: `0?[0-9]|[1-5][0-9]`,
@@ -141,6 +117,7 @@ var (
 		name:         "hour",
 		min:          0,
 		max:          23,
+		hashmin:      0,
 		hashmax:      23,
 		defaultList:  genericDefaultList[0:24],
 		valuePattern: `0?[0-9]|1[0-9]|2[0-3]`,
@@ -150,6 +127,7 @@ var (
 		name:         "day-of-month",
 		min:          1,
 		max:          31,
+		hashmin:      1,
 		defaultList:  genericDefaultList[1:32],
 		valuePattern: `0?[1-9]|[12][0-9]|3[01]`,
 		atoi:         atoi,
@@ -166,6 +144,7 @@ var (
 		name:         "month",
 		min:          1,
 		max:          12,
+		hashmin:      1,
 		hashmax:      12,
 		defaultList:  genericDefaultList[1:13],
 		valuePattern: `0?[1-9]|1[012]|jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec|january|february|march|april|march|april|june|july|august|september|october|november|december`,
@@ -177,6 +156,7 @@ var (
 		name:         "day-of-week",
 		min:          0,
 		max:          6,
+		hashmin:      0,
 		hashmax:      6,
 		defaultList:  genericDefaultList[0:7],
 		valuePattern: `0?[0-7]|sun|mon|tue|wed|thu|fri|sat|sunday|monday|tuesday|wednesday|thursday|friday|saturday`,
@@ -185,18 +165,23 @@ var (
 		},
 	}
 	yearDescriptor = fieldDescriptor{
-		name:         "year",
-		min:          1970,
-		max:          2099,
+		name: "year",
+		min:  1,
+		max:  9999,
+		// Hashes are chosen in the range that was supported before years
+		// were extended, so that hashed years do not change.
+		hashmin:      1970,
 		hashmax:      2099,
-		defaultList:  yearDefaultList[:],
-		valuePattern: `19[789][0-9]|20[0-9]{2}`,
+		valuePattern: `[0-9]{1,4}`,
 		atoi:         atoi,
 	}
 )
 
 /******************************************************************************/
 
+// maxDaysBeforeLastDayOfMonth is the largest `n` in `L-n`, as in Quartz.
+const maxDaysBeforeLastDayOfMonth = 30
+
 var (
 	layoutWildcard            = `^\*{1,2}$|^\?{1,2}$`
 	layoutHashOnly            = `^h$`
@@ -210,10 +195,13 @@ var (
 	layoutValueAndInterval    = `^(%value%)/(\d+)$`
 	layoutRangeAndInterval    = `^(%value%)-(%value%)/(\d+)$`
 	layoutLastDom             = `^l$`
+	layoutLastDomOffset       = `^l-(\d+)(w?)$`
 	layoutWorkdom             = `^(%value%)w$`
 	layoutLastWorkdom         = `^lw$`
 	layoutDowOfLastWeek       = `^(%value%)l$`
 	layoutDowOfSpecificWeek   = `^(%value%)#([1-5])$`
+	layoutNoSpec              = `^\?{1,2}$`
+	layoutNumbers             = `^(\d+)(?:-(\d+))?(?:/\d+)?$`
 	fieldFinder               = regexp.MustCompile(`\S+`)
 	entryFinder               = regexp.MustCompile(`[^,]+`)
 	layoutRegexp              = make(map[string]*regexp.Regexp)
@@ -230,44 +218,106 @@ var cronNormalizer = strings.NewReplacer(
 	"@daily", "0 0 0 * * * *",
 	"@hourly", "0 0 * * * * *")
 
+// quartzNormalizer expands the predefined aliases for CronFormatQuartz, which
+// requires `?` in one of the day fields and numbers days of week from 1.
+var quartzNormalizer = strings.NewReplacer(
+	"@yearly", "0 0 0 1 1 ? *",
+	"@annually", "0 0 0 1 1 ? *",
+	"@monthly", "0 0 0 1 * ? *",
+	"@weekly", "0 0 0 ? * 1 *",
+	"@daily", "0 0 0 * * ? *",
+	"@hourly", "0 0 * * * ? *")
+
+// lineHandler returns the fields of the cron line, with predefined aliases expanded.
+// For `@every` interval schedules, the interval is parsed and no fields are returned.
+func (expr *Expression) lineHandler(cronLine string) (string, error) {
+	cron, err := expr.timeZoneHandler(cronLine)
+	if err != nil {
+		return "", err
+	}
+	if everyFinder.MatchString(cron) {
+		return "", expr.everyHandler(cron)
+	}
+	return cronNormalizer.Replace(cron), nil
+}
+
 /******************************************************************************/
 
 func (expr *Expression) secondFieldHandler(s string) error {
-	var err error
-	expr.secondList, err = genericFieldHandler(s, secondDescriptor, expr.hash)
+	list, err := genericFieldHandler(s, secondDescriptor, expr.hash)
+	expr.seconds = newBitset(list)
 	return err
 }
 
 /******************************************************************************/
 
 func (expr *Expression) minuteFieldHandler(s string) error {
-	var err error
-	expr.minuteList, err = genericFieldHandler(s, minuteDescriptor, expr.hash)
+	list, err := genericFieldHandler(s, minuteDescriptor, expr.hash)
+	expr.minutes = newBitset(list)
 	return err
 }
 
 /******************************************************************************/
 
 func (expr *Expression) hourFieldHandler(s string) error {
-	var err error
-	expr.hourList, err = genericFieldHandler(s, hourDescriptor, expr.hash)
+	list, err := genericFieldHandler(s, hourDescriptor, expr.hash)
+	expr.hours = newBitset(list)
 	return err
 }
 
 /******************************************************************************/
 
 func (expr *Expression) monthFieldHandler(s string) error {
-	var err error
-	expr.monthList, err = genericFieldHandler(s, monthDescriptor, expr.hash)
+	list, err := genericFieldHandler(s, monthDescriptor, expr.hash)
+	expr.months = newBitset(list)
 	return err
 }
 
 /******************************************************************************/
 
+// yearFieldHandler works like genericFieldHandler, but without the set of
+// values, since the year field has thousands of them. All years are kept as a
+// nil list rather than being materialized, so that e.g. `*/1` or `1-9999` lists
+// no years at all.
 func (expr *Expression) yearFieldHandler(s string) error {
-	var err error
-	expr.yearList, err = genericFieldHandler(s, yearDescriptor, expr.hash)
-	return err
+	directives, err := genericFieldParse(s, yearDescriptor, expr.hash)
+	if err != nil {
+		return err
+	}
+	var list []int
+	allYears := false
+	for _, directive := range directives {
+		// validate directive
+		if err := directive.IsValid(yearDescriptor.min, yearDescriptor.max); err != nil {
+			return err
+		}
+		switch directive.kind {
+		case none:
+			return newDirectiveError(ReasonUnknownToken, directive, "syntax error in %s field: '%s'", yearDescriptor.name, s[directive.sbeg:directive.send])
+		case one:
+			list = append(list, directive.first)
+		case span:
+			if directive.step == 1 && directive.first == yearDescriptor.min && directive.last == yearDescriptor.max {
+				allYears = true
+				continue
+			}
+			for year := directive.first; year <= directive.last; year += directive.step {
+				list = append(list, year)
+			}
+		case all:
+			expr.yearList = yearDescriptor.defaultList
+			return nil
+		}
+	}
+	if allYears {
+		list = nil
+	}
+	list = sortedUnique(list)
+	if len(list) == yearDescriptor.max-yearDescriptor.min+1 {
+		list = nil
+	}
+	expr.yearList = list
+	return nil
 }
 
 /******************************************************************************/
@@ -291,17 +341,27 @@ type cronDirective struct {
 
 // IsValid is checking if directive valid
 func (directive cronDirective) IsValid(min, max int) error {
+	if directive.kind == one {
+		if directive.first < min {
+			return newDirectiveError(ReasonBelowMinimum, &directive, "value (%d) below minimum (%d): %s",
+				directive.first, min, directive.item)
+		}
+		if directive.first > max {
+			return newDirectiveError(ReasonAboveMaximum, &directive, "value (%d) above maximum (%d): %s",
+				directive.first, max, directive.item)
+		}
+	}
 	if directive.kind == span {
 		if directive.first < min {
-			return fmt.Errorf("beginning of range (%d) below minimum (%d): %s",
+			return newDirectiveError(ReasonBelowMinimum, &directive, "beginning of range (%d) below minimum (%d): %s",
 				directive.first, min, directive.item)
 		}
 		if directive.last > max {
-			return fmt.Errorf("end of range (%d) above maximum (%d): %s",
+			return newDirectiveError(ReasonAboveMaximum, &directive, "end of range (%d) above maximum (%d): %s",
 				directive.last, max, directive.item)
 		}
 		if directive.first > directive.last {
-			return fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s",
+			return newDirectiveError(ReasonInvertedRange, &directive, "beginning of range (%d) beyond end of range (%d): %s",
 				directive.first, directive.last, directive.item)
 		}
 	}
@@ -321,7 +381,7 @@ func genericFieldHandler(s string, desc fieldDescriptor, hash *hash) ([]int, err
 		}
 		switch directive.kind {
 		case none:
-			return nil, fmt.Errorf("syntax error in %s field: '%s'", desc.name, s[directive.sbeg:directive.send])
+			return nil, newDirectiveError(ReasonUnknownToken, directive, "syntax error in %s field: '%s'", desc.name, s[directive.sbeg:directive.send])
 		case one:
 			populateOne(values, directive.first)
 		case span:
@@ -335,9 +395,9 @@ func genericFieldHandler(s string, desc fieldDescriptor, hash *hash) ([]int, err
 
 func (expr *Expression) dowFieldHandler(s string) error {
 	expr.daysOfWeekRestricted = true
-	expr.daysOfWeek = make(map[int]bool)
-	expr.lastWeekDaysOfWeek = make(map[int]bool)
-	expr.specificWeekDaysOfWeek = make(map[int]bool)
+	expr.daysOfWeek = 0
+	expr.lastWeekDaysOfWeek = 0
+	expr.specificWeekDaysOfWeek = 0
 
 	directives, err := genericFieldParse(s, dowDescriptor, expr.hash)
 	if err != nil {
@@ -352,18 +412,18 @@ func (expr *Expression) dowFieldHandler(s string) error {
 			// `5L`
 			pairs := makeLayoutRegexp(layoutDowOfLastWeek, dowDescriptor.valuePattern).FindStringSubmatchIndex(snormal)
 			if len(pairs) > 0 {
-				populateOne(expr.lastWeekDaysOfWeek, dowDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
+				expr.lastWeekDaysOfWeek.add(dowDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
 			} else {
 				// `5#3`
 				pairs := makeLayoutRegexp(layoutDowOfSpecificWeek, dowDescriptor.valuePattern).FindStringSubmatchIndex(snormal)
 				if len(pairs) > 0 {
-					populateOne(expr.specificWeekDaysOfWeek, (dowDescriptor.atoi(snormal[pairs[4]:pairs[5]])-1)*7+(dowDescriptor.atoi(snormal[pairs[2]:pairs[3]])%7))
+					expr.specificWeekDaysOfWeek.add((dowDescriptor.atoi(snormal[pairs[4]:pairs[5]])-1)*7 + (dowDescriptor.atoi(snormal[pairs[2]:pairs[3]]) % 7))
 				} else {
-					return fmt.Errorf("syntax error in day-of-week field: '%s'", sdirective)
+					return newDirectiveError(ReasonUnknownToken, directive, "syntax error in day-of-week field: '%s'", sdirective)
 				}
 			}
 		case one:
-			populateOne(expr.daysOfWeek, directive.first)
+			expr.daysOfWeek.add(directive.first)
 		case span:
 			// To properly handle spans that end in 7 (Sunday)
 			if directive.last == 0 {
@@ -372,9 +432,9 @@ func (expr *Expression) dowFieldHandler(s string) error {
 			if err := directive.IsValid(dowDescriptor.min, dowDescriptor.max); err != nil {
 				return err
 			}
-			populateMany(expr.daysOfWeek, directive.first, directive.last, directive.step)
+			expr.daysOfWeek.addRange(directive.first, directive.last, directive.step)
 		case all:
-			populateMany(expr.daysOfWeek, directive.first, directive.last, directive.step)
+			expr.daysOfWeek.addRange(directive.first, directive.last, directive.step)
 			expr.daysOfWeekRestricted = false
 		}
 	}
@@ -385,8 +445,10 @@ func (expr *Expression) domFieldHandler(s string) error {
 	expr.daysOfMonthRestricted = true
 	expr.lastDayOfMonth = false
 	expr.lastWorkdayOfMonth = false
-	expr.daysOfMonth = make(map[int]bool)     // days of month map
-	expr.workdaysOfMonth = make(map[int]bool) // work days of month map
+	expr.daysOfMonth = 0
+	expr.workdaysOfMonth = 0
+	expr.daysBeforeLastDayOfMonth = 0
+	expr.workdaysBeforeLastDayOfMonth = 0
 
 	directives, err := genericFieldParse(s, domDescriptor, expr.hash)
 	if err != nil {
@@ -409,28 +471,51 @@ func (expr *Expression) domFieldHandler(s string) error {
 				// `LW`
 				if makeLayoutRegexp(layoutLastWorkdom, domDescriptor.valuePattern).MatchString(snormal) {
 					expr.lastWorkdayOfMonth = true
+				} else if pairs := makeLayoutRegexp(layoutLastDomOffset, domDescriptor.valuePattern).FindStringSubmatchIndex(snormal); len(pairs) > 0 {
+					// `L-3` or `L-3W`
+					offset := atoi(snormal[pairs[2]:pairs[3]])
+					if offset < 1 {
+						return newDirectiveError(ReasonBelowMinimum, directive, "offset from last day of month (%d) below minimum (1): %s",
+							offset, sdirective)
+					}
+					if offset > maxDaysBeforeLastDayOfMonth {
+						return newDirectiveError(ReasonAboveMaximum, directive, "offset from last day of month (%s) above maximum (%d): %s",
+							snormal[pairs[2]:pairs[3]], maxDaysBeforeLastDayOfMonth, sdirective)
+					}
+					if pairs[4] < pairs[5] {
+						expr.workdaysBeforeLastDayOfMonth.add(offset)
+					} else {
+						expr.daysBeforeLastDayOfMonth.add(offset)
+					}
 				} else {
 					// `15W`
 					pairs := makeLayoutRegexp(layoutWorkdom, domDescriptor.valuePattern).FindStringSubmatchIndex(snormal)
 					if len(pairs) > 0 {
-						populateOne(expr.workdaysOfMonth, domDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
+						expr.workdaysOfMonth.add(domDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
 					} else {
-						return fmt.Errorf("syntax error in day-of-month field: '%s'", sdirective)
+						return newDirectiveError(ReasonUnknownToken, directive, "syntax error in day-of-month field: '%s'", sdirective)
 					}
 				}
 			}
 		case one:
-			populateOne(expr.daysOfMonth, directive.first)
+			expr.daysOfMonth.add(directive.first)
 		case span:
-			populateMany(expr.daysOfMonth, directive.first, directive.last, directive.step)
+			expr.daysOfMonth.addRange(directive.first, directive.last, directive.step)
 		case all:
-			populateMany(expr.daysOfMonth, directive.first, directive.last, directive.step)
+			expr.daysOfMonth.addRange(directive.first, directive.last, directive.step)
 			expr.daysOfMonthRestricted = false
 		}
 	}
 	return nil
 }
 
+// hasSpecialDaysOfMonth returns whether the day-of-month field has `L`, `LW`,
+// `L-n`, `L-nW` or `nW` directives.
+func (expr *Expression) hasSpecialDaysOfMonth() bool {
+	return expr.lastDayOfMonth || expr.lastWorkdayOfMonth || expr.daysBeforeLastDayOfMonth != 0 ||
+		expr.workdaysBeforeLastDayOfMonth != 0 || expr.workdaysOfMonth != 0
+}
+
 /******************************************************************************/
 
 func populateOne(values map[int]bool, v int) {
@@ -454,13 +539,26 @@ func toList(set map[int]bool) []int {
 	return list
 }
 
+// sortedUnique sorts a list of values in place and removes its duplicates.
+func sortedUnique(list []int) []int {
+	sort.Ints(list)
+	n := 0
+	for i, v := range list {
+		if i == 0 || v != list[n-1] {
+			list[n] = v
+			n++
+		}
+	}
+	return list[:n]
+}
+
 /******************************************************************************/
 
 func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirective, error) {
 	// At least one entry must be present
 	indices := entryFinder.FindAllStringIndex(s, -1)
 	if len(indices) == 0 {
-		return nil, fmt.Errorf("%s field: missing directive", desc.name)
+		return nil, newDirectiveError(ReasonMissingDirective, &cronDirective{send: len(s)}, "%s field: missing directive", desc.name)
 	}
 
 	directives := make([]*cronDirective, 0, len(indices))
@@ -485,10 +583,10 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 		// `H`
 		if makeLayoutRegexp(layoutHashOnly, desc.valuePattern).MatchString(snormal) {
 			if hash == nil {
-				return nil, makeErrorNoHashInput(snormal)
+				return nil, makeErrorNoHashInput(&directive)
 			}
 			directive.kind = one
-			directive.first = hash.GetValueForField(desc.min, desc.hashmax, desc.name)
+			directive.first = hash.GetValueForField(desc.hashmin, desc.hashmax, desc.name)
 			directives = append(directives, &directive)
 			continue
 		}
@@ -513,7 +611,7 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 		pairs = makeLayoutRegexp(layoutHashRange, desc.valuePattern).FindStringSubmatchIndex(snormal)
 		if len(pairs) > 0 {
 			if hash == nil {
-				return nil, makeErrorNoHashInput(snormal)
+				return nil, makeErrorNoHashInput(&directive)
 			}
 			directive.kind = one
 			directive.first = hash.GetValueForField(desc.atoi(snormal[pairs[2]:pairs[3]]),
@@ -529,7 +627,7 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 			directive.last = desc.max
 			directive.step = atoi(snormal[pairs[2]:pairs[3]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			directives = append(directives, &directive)
 			continue
@@ -542,7 +640,7 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 			directive.last = desc.max
 			directive.step = atoi(snormal[pairs[2]:pairs[3]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			directives = append(directives, &directive)
 			continue
@@ -555,7 +653,7 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 			directive.last = desc.max
 			directive.step = atoi(snormal[pairs[4]:pairs[5]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			directives = append(directives, &directive)
 			continue
@@ -564,14 +662,14 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 		pairs = makeLayoutRegexp(layoutHashInterval, desc.valuePattern).FindStringSubmatchIndex(snormal)
 		if len(pairs) > 0 {
 			if hash == nil {
-				return nil, makeErrorNoHashInput(snormal)
+				return nil, makeErrorNoHashInput(&directive)
 			}
 			directive.kind = span
 			directive.first = desc.min
 			directive.last = desc.max
 			directive.step = atoi(snormal[pairs[2]:pairs[3]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			directive.first = hash.GetValueForField(0, directive.step-1, desc.name)
 			directives = append(directives, &directive)
@@ -585,7 +683,7 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 			directive.last = desc.atoi(snormal[pairs[4]:pairs[5]])
 			directive.step = atoi(snormal[pairs[6]:pairs[7]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			directives = append(directives, &directive)
 			continue
@@ -596,20 +694,24 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 		pairs = makeLayoutRegexp(layoutHashRangeInterval, desc.valuePattern).FindStringSubmatchIndex(snormal)
 		if len(pairs) > 0 {
 			if hash == nil {
-				return nil, makeErrorNoHashInput(snormal)
+				return nil, makeErrorNoHashInput(&directive)
 			}
 			directive.kind = span
 			directive.first = desc.atoi(snormal[pairs[2]:pairs[3]])
 			directive.last = desc.atoi(snormal[pairs[4]:pairs[5]])
 			directive.step = atoi(snormal[pairs[6]:pairs[7]])
 			if directive.step < 1 || directive.step > desc.max {
-				return nil, fmt.Errorf("invalid interval %s", snormal)
+				return nil, newDirectiveError(ReasonStepOutOfRange, &directive, "invalid interval %s", snormal)
 			}
 			// Increase first by hash % step.
 			directive.first += hash.GetValueForField(0, directive.step-1, desc.name)
 			directives = append(directives, &directive)
 			continue
 		}
+		// `60`, `1-60` or `1-60/5` with numbers out of the field's bounds
+		if err := outOfBoundsError(&directive, desc); err != nil {
+			return nil, err
+		}
 		// No behavior for this one, let caller deal with it
 		directive.kind = none
 		directives = append(directives, &directive)
@@ -617,6 +719,28 @@ func genericFieldParse(s string, desc fieldDescriptor, hash *hash) ([]*cronDirec
 	return directives, nil
 }
 
+// outOfBoundsError returns an error if the directive is a value, a range or an
+// interval whose numbers are not all values of the field, or nil otherwise.
+// Such numbers are either above the maximum of the field, or zero, which is
+// below the minimum of the fields starting from 1.
+func outOfBoundsError(directive *cronDirective, desc fieldDescriptor) error {
+	pairs := makeLayoutRegexp(layoutNumbers, "").FindStringSubmatch(directive.item)
+	if pairs == nil {
+		return nil
+	}
+	for _, number := range pairs[1:] {
+		if number == "" || makeLayoutRegexp(layoutValue, desc.valuePattern).MatchString(number) {
+			continue
+		}
+		reason := ReasonBelowMinimum
+		if v, err := strconv.Atoi(number); err != nil || v > desc.max {
+			reason = ReasonAboveMaximum
+		}
+		return newDirectiveError(reason, directive, "syntax error in %s field: '%s'", desc.name, directive.item)
+	}
+	return nil
+}
+
 /******************************************************************************/
 
 func makeLayoutRegexp(layout, value string) *regexp.Regexp {
@@ -1,7 +1,7 @@
 package cronexpr
 
 import (
-	"fmt"
+	"strings"
 )
 
 var (
@@ -21,6 +21,7 @@ var (
 		name:         "day-of-week",
 		min:          0,
 		max:          6,
+		hashmin:      0,
 		hashmax:      6,
 		defaultList:  genericDefaultList[1:7],
 		valuePattern: `0?[1-7]|sun|mon|tue|wed|thu|fri|sat|sunday|monday|tuesday|wednesday|thursday|friday|saturday`,
@@ -33,15 +34,56 @@ var (
 // quartzExpression implements custom parsing for the Quartz scheduler format.
 type quartzExpression struct {
 	*Expression
+
+	// Whether the day-of-month field is `?`.
+	daysOfMonthNoSpec bool
+}
+
+// quartzFieldCount is the number of fields of a Quartz expression without its
+// optional year field.
+const quartzFieldCount = 6
+
+// lineHandler overrides the default cron line handling, as Quartz expressions
+// start with a seconds field, and the year field is the optional one: 6 fields
+// are read as seconds to day of week rather than minute to year.
+//
+// Predefined aliases are expanded with `?` in one of the day fields.
+func (expr *quartzExpression) lineHandler(cronLine string) (string, error) {
+	cron, err := expr.Expression.lineHandler(quartzNormalizer.Replace(cronLine))
+	if err != nil {
+		return "", err
+	}
+	if len(fieldFinder.FindAllStringIndex(cron, -1)) == quartzFieldCount {
+		cron += " *"
+	}
+	return cron, nil
+}
+
+// domFieldHandler overrides the default day of month parsing, to keep track of
+// whether the day-of-month field is `?`.
+func (expr *quartzExpression) domFieldHandler(s string) error {
+	if err := expr.Expression.domFieldHandler(s); err != nil {
+		return err
+	}
+	expr.daysOfMonthNoSpec = isNoSpec(s)
+
+	// All days of month is the same as no restriction at all.
+	if expr.daysOfMonth == domDescriptor.all() && !expr.hasSpecialDaysOfMonth() {
+		expr.daysOfMonthRestricted = false
+	}
+	return nil
 }
 
 // dowFieldHandler overrides the default day of week parsing.
 // Day of week uses 1-7 for SUN-SAT, instead of 0-6 on standard implementations.
+//
+// As in Quartz, exactly one of the day-of-month and day-of-week fields must be
+// `?`. The other field is then the only restriction on days.
 func (expr *quartzExpression) dowFieldHandler(s string) error {
 	expr.daysOfWeekRestricted = true
-	expr.daysOfWeek = make(map[int]bool)
-	expr.lastWeekDaysOfWeek = make(map[int]bool)
-	expr.specificWeekDaysOfWeek = make(map[int]bool)
+	expr.daysOfWeek = 0
+	expr.lastWeekDaysOfWeek = 0
+	expr.specificWeekDaysOfWeek = 0
 
 	// Use custom descriptor
 	directives, err := genericFieldParse(s, quartzDowDescriptor, expr.hash)
@@ -53,10 +95,27 @@ func (expr *quartzExpression) dowFieldHandler(s string) error {
 		sdirective := s[directive.sbeg:directive.send]
 		switch directive.kind {
 		case none:
-			// not implemented.
-			return fmt.Errorf("syntax error in day-of-week field: '%s'", sdirective)
+			snormal := strings.ToLower(sdirective)
+			// `L`, which is the last day of the week, i.e. Saturday
+			if makeLayoutRegexp(layoutLastDom, quartzDowDescriptor.valuePattern).MatchString(snormal) {
+				expr.daysOfWeek.add(quartzDowMax)
+				continue
+			}
+			// `6L`
+			pairs := makeLayoutRegexp(layoutDowOfLastWeek, quartzDowDescriptor.valuePattern).FindStringSubmatchIndex(snormal)
+			if len(pairs) > 0 {
+				expr.lastWeekDaysOfWeek.add(quartzDowDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
+				continue
+			}
+			// `6#3`
+			pairs = makeLayoutRegexp(layoutDowOfSpecificWeek, quartzDowDescriptor.valuePattern).FindStringSubmatchIndex(snormal)
+			if len(pairs) > 0 {
+				expr.specificWeekDaysOfWeek.add((atoi(snormal[pairs[4]:pairs[5]])-1)*7 + quartzDowDescriptor.atoi(snormal[pairs[2]:pairs[3]]))
+				continue
+			}
+			return newDirectiveError(ReasonUnknownToken, directive, "syntax error in day-of-week field: '%s'", sdirective)
 		case one:
-			populateOne(expr.daysOfWeek, directive.first)
+			expr.daysOfWeek.add(directive.first)
 		case span:
 			// To properly handle spans that end in 7 (Sunday)
 			if directive.last == 0 {
@@ -66,12 +125,32 @@ func (expr *quartzExpression) dowFieldHandler(s string) error {
 			if err := directive.IsValid(quartzDowMin, quartzDowMax); err != nil {
 				return err
 			}
-			populateMany(expr.daysOfWeek, directive.first, directive.last, directive.step)
+			expr.daysOfWeek.addRange(directive.first, directive.last, directive.step)
 		case all:
-			populateMany(expr.daysOfWeek, directive.first, directive.last, directive.step)
+			expr.daysOfWeek.addRange(directive.first, directive.last, directive.step)
 			expr.daysOfWeekRestricted = false
 		}
 	}
 
+	// All days of week is the same as no restriction at all.
+	if expr.daysOfWeek == dowDescriptor.all() && expr.lastWeekDaysOfWeek == 0 && expr.specificWeekDaysOfWeek == 0 {
+		expr.daysOfWeekRestricted = false
+	}
+
+	field := &cronDirective{send: len(s)}
+	if expr.daysOfMonthNoSpec && isNoSpec(s) {
+		return newDirectiveError(ReasonDayFieldConflict, field,
+			"'?' can only be used in one of the day-of-month and day-of-week fields")
+	}
+	if !expr.daysOfMonthNoSpec && !isNoSpec(s) {
+		return newDirectiveError(ReasonDayFieldConflict, field,
+			"one of the day-of-month and day-of-week fields must be '?'")
+	}
+
 	return nil
 }
+
+// isNoSpec returns whether a day field is `?`.
+func isNoSpec(s string) bool {
+	return makeLayoutRegexp(layoutNoSpec, "").MatchString(s)
+}
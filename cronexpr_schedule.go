@@ -0,0 +1,103 @@
+package cronexpr
+
+import (
+	"time"
+)
+
+// Schedule is a set of time instants, such as those matching an Expression.
+// Schedules can be combined with Union, Intersect and Except.
+type Schedule interface {
+	// Next returns the earliest time instant of the schedule following
+	// `fromTime`, or the zero value of time.Time if there is none or if
+	// `fromTime` is itself a zero value.
+	Next(fromTime time.Time) time.Time
+}
+
+var _ Schedule = &Expression{}
+
+// maxScheduleSteps bounds the number of time instants that Intersect and
+// Except go through to find one that is in all of their schedules or not
+// excluded, such that they return the zero value of time.Time rather than
+// searching forever when there is none, e.g. for Except(expr, expr).
+const maxScheduleSteps = 1000000
+
+// Union returns a Schedule made up of the time instants of all of the given
+// schedules. A time instant of several of them is only returned once.
+func Union(schedules ...Schedule) Schedule {
+	return unionSchedule(schedules)
+}
+
+type unionSchedule []Schedule
+
+func (s unionSchedule) Next(fromTime time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range s {
+		t := schedule.Next(fromTime)
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// Intersect returns a Schedule made up of the time instants that are in every
+// one of the given schedules, e.g. Intersect(MustParse("0 0 13 * *"),
+// MustParse("0 0 * * 5")) is every Friday the 13th. It has no time instants if
+// no schedules are given.
+func Intersect(schedules ...Schedule) Schedule {
+	return intersectSchedule(schedules)
+}
+
+type intersectSchedule []Schedule
+
+func (s intersectSchedule) Next(fromTime time.Time) time.Time {
+	if len(s) == 0 || fromTime.IsZero() {
+		return time.Time{}
+	}
+
+	// Each schedule in turn moves the candidate forward to its earliest time
+	// instant not before it, until all of them agree on it.
+	candidate := s[0].Next(fromTime)
+	agreed := 1
+	for step := 0; step < maxScheduleSteps && !candidate.IsZero(); step++ {
+		if agreed == len(s) {
+			return candidate
+		}
+		t := nextOrSame(s[(step+1)%len(s)], candidate)
+		if t.Equal(candidate) {
+			agreed++
+		} else {
+			candidate = t
+			agreed = 1
+		}
+	}
+	return time.Time{}
+}
+
+// Except returns a Schedule made up of the time instants of `schedule` which are
+// not time instants of `excluded`, e.g. Except(MustParse("*/5 * * * *"),
+// MustParse("* 2-3 * * 0")) is every 5 minutes, except from 2:00 to 3:59 on Sundays.
+func Except(schedule, excluded Schedule) Schedule {
+	return &exceptSchedule{schedule: schedule, excluded: excluded}
+}
+
+type exceptSchedule struct {
+	schedule Schedule
+	excluded Schedule
+}
+
+func (s *exceptSchedule) Next(fromTime time.Time) time.Time {
+	t := s.schedule.Next(fromTime)
+	for step := 0; step < maxScheduleSteps && !t.IsZero(); step++ {
+		if !nextOrSame(s.excluded, t).Equal(t) {
+			return t
+		}
+		t = s.schedule.Next(t)
+	}
+	return time.Time{}
+}
+
+// nextOrSame returns the earliest time instant of `schedule` which is not before `t`.
+func nextOrSame(schedule Schedule, t time.Time) time.Time {
+	return schedule.Next(t.Add(-time.Nanosecond))
+}
@@ -0,0 +1,88 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedules(t *testing.T) {
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     []string
+	}{
+		{
+			name:     "union",
+			schedule: Union(MustParse("0 9 * * 1-5"), MustParse("0 12 * * 0,6")),
+			want:     []string{"2021-03-01T09:00:00Z", "2021-03-02T09:00:00Z", "2021-03-03T09:00:00Z", "2021-03-04T09:00:00Z", "2021-03-05T09:00:00Z", "2021-03-06T12:00:00Z"},
+		},
+		{
+			name:     "union of overlapping schedules",
+			schedule: Union(MustParse("*/20 * * * *"), MustParse("*/30 * * * *")),
+			want:     []string{"2021-03-01T00:20:00Z", "2021-03-01T00:30:00Z", "2021-03-01T00:40:00Z", "2021-03-01T01:00:00Z", "2021-03-01T01:20:00Z"},
+		},
+		{
+			name:     "union with a finished schedule",
+			schedule: Union(MustParse("0 0 1 1 * 2020"), MustParse("0 0 1 * *")),
+			want:     []string{"2021-04-01T00:00:00Z", "2021-05-01T00:00:00Z"},
+		},
+		{
+			name:     "intersection",
+			schedule: Intersect(MustParse("0 0 13 * *"), MustParse("0 0 * * 5")),
+			want:     []string{"2021-08-13T00:00:00Z", "2022-05-13T00:00:00Z", "2023-01-13T00:00:00Z"},
+		},
+		{
+			name:     "intersection of three schedules",
+			schedule: Intersect(MustParse("*/20 * * * *"), MustParse("*/30 * * * *"), MustParse("0 */2 * * *")),
+			want:     []string{"2021-03-01T02:00:00Z", "2021-03-01T04:00:00Z", "2021-03-01T06:00:00Z"},
+		},
+		{
+			name:     "empty intersection",
+			schedule: Intersect(MustParse("0 0 * * 1 2021"), MustParse("0 0 * * 2 2021")),
+		},
+		{
+			name:     "intersection of no schedules",
+			schedule: Intersect(),
+		},
+		{
+			name:     "difference",
+			schedule: Except(MustParse("0 * * * *"), MustParse("* 1-3 * * *")),
+			want:     []string{"2021-03-01T04:00:00Z", "2021-03-01T05:00:00Z"},
+		},
+		{
+			name:     "difference of a schedule from itself",
+			schedule: Except(MustParse("0 0 * * * 2021"), MustParse("0 0 * * * 2021")),
+		},
+		{
+			name: "nested",
+			schedule: Except(
+				Union(MustParse("0 9 * * 1-5"), Intersect(MustParse("0 */6 * * *"), MustParse("0 * * * 6"))),
+				Union(MustParse("0 9 2 * *"), MustParse("0 6 * * *")),
+			),
+			want: []string{"2021-03-01T09:00:00Z", "2021-03-03T09:00:00Z", "2021-03-04T09:00:00Z", "2021-03-05T09:00:00Z", "2021-03-06T00:00:00Z", "2021-03-06T12:00:00Z", "2021-03-06T18:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			next := from
+			for i := 0; i < len(tt.want)+1; i++ {
+				next = tt.schedule.Next(next)
+				if next.IsZero() {
+					break
+				}
+				got = append(got, next.Format(time.RFC3339))
+			}
+			if len(got) > len(tt.want) {
+				got = got[:len(tt.want)]
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.True(t, tt.schedule.Next(time.Time{}).IsZero())
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package cronexpr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Original returns the cron line exactly as it was given when the Expression was parsed.
+func (expr *Expression) Original() string {
+	return expr.expression
+}
+
+// String returns the normalized form of the Expression, made up of all 7 fields
+// (second, minute, hour, day of month, month, day of week and year).
+//
+// The normalized form is built from the parsed values, such that hashes and
+// predefined aliases are resolved: `H H(0-7) * * *` may be returned as
+// `0 37 5 * * * *`. Ranges and intervals are compressed to the `a-b` and
+// `a-b/c` notations where possible, while `L`, `W`, `LW`, `5L` and `2#3` are
+// kept as is.
+//
+// An `@every` interval schedule is returned as `@every`, the interval and the
+// phase if not zero, with a hashed phase resolved, e.g. `@every 1h30m0s 17m3s`.
+//
+// With CronFormatQuartz, the day-of-week field is numbered from 1, and the
+// day field which does not restrict days is `?`.
+//
+// With CronFormatKubernetes, the normalized form is made up of the 5 fields
+// from minute to day of week instead. It is preceded by a `CRON_TZ=` prefix if
+// the Expression has a time zone.
+//
+// Parsing the normalized form again with the same CronFormat, DayMatchMode and
+// DSTPolicy returns an equivalent Expression.
+func (expr *Expression) String() string {
+	var prefix string
+	if expr.location != nil {
+		prefix = "CRON_TZ=" + expr.location.String() + " "
+	}
+	if expr.every != 0 {
+		if expr.everyPhase != 0 {
+			return prefix + "@every " + expr.every.String() + " " + expr.everyPhase.String()
+		}
+		return prefix + "@every " + expr.every.String()
+	}
+
+	fields := []string{
+		formatList(expr.seconds.list(), secondDescriptor),
+		formatList(expr.minutes.list(), minuteDescriptor),
+		formatList(expr.hours.list(), hourDescriptor),
+		expr.formatDaysOfMonth(),
+		formatList(expr.months.list(), monthDescriptor),
+		expr.formatDaysOfWeek(),
+		formatList(expr.yearList, yearDescriptor),
+	}
+	switch expr.format {
+	case CronFormatQuartz:
+		// One of the day fields must be `?`, which is the same as `*`.
+		if expr.daysOfWeekRestricted {
+			fields[3] = "?"
+		} else {
+			fields[5] = "?"
+		}
+	case CronFormatKubernetes:
+		fields = fields[1:6]
+	}
+	return prefix + strings.Join(fields, " ")
+}
+
+func (expr *Expression) formatDaysOfMonth() string {
+	if !expr.daysOfMonthRestricted {
+		return "*"
+	}
+
+	var items []string
+	if expr.daysOfMonth != 0 {
+		items = append(items, compressList(expr.daysOfMonth.list(), domDescriptor))
+	}
+	for _, v := range expr.workdaysOfMonth.list() {
+		items = append(items, strconv.Itoa(v)+"W")
+	}
+	if expr.lastDayOfMonth {
+		items = append(items, "L")
+	}
+	for _, v := range expr.daysBeforeLastDayOfMonth.list() {
+		items = append(items, "L-"+strconv.Itoa(v))
+	}
+	if expr.lastWorkdayOfMonth {
+		items = append(items, "LW")
+	}
+	for _, v := range expr.workdaysBeforeLastDayOfMonth.list() {
+		items = append(items, "L-"+strconv.Itoa(v)+"W")
+	}
+	return strings.Join(items, ",")
+}
+
+func (expr *Expression) formatDaysOfWeek() string {
+	if !expr.daysOfWeekRestricted {
+		return "*"
+	}
+
+	// The Quartz format numbers days of week from 1 instead of 0.
+	desc, offset := dowDescriptor, 0
+	if expr.format == CronFormatQuartz {
+		desc, offset = quartzDowDescriptor, 1
+	}
+
+	var items []string
+	if expr.daysOfWeek != 0 {
+		list := expr.daysOfWeek.list()
+		for i := range list {
+			list[i] += offset
+		}
+		desc.min += offset
+		desc.max += offset
+		items = append(items, compressList(list, desc))
+	}
+	for _, v := range expr.lastWeekDaysOfWeek.list() {
+		items = append(items, strconv.Itoa(v+offset)+"L")
+	}
+	for _, v := range expr.specificWeekDaysOfWeek.list() {
+		items = append(items, strconv.Itoa(v%7+offset)+"#"+strconv.Itoa(v/7+1))
+	}
+	return strings.Join(items, ",")
+}
+
+// formatList returns the field for a sorted list of values, which is `*` if
+// all of the field's values are present.
+func formatList(list []int, desc fieldDescriptor) string {
+	if list == nil || len(list) == desc.max-desc.min+1 {
+		return "*"
+	}
+	return compressList(list, desc)
+}
+
+// compressList returns the shortest comma-separated directives for a sorted
+// list of values, see listSegments.
+func compressList(list []int, desc fieldDescriptor) string {
+	segments := listSegments(list)
+	items := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch {
+		case seg.first == seg.last:
+			items = append(items, strconv.Itoa(seg.first))
+		case seg.step == 1:
+			items = append(items, strconv.Itoa(seg.first)+"-"+strconv.Itoa(seg.last))
+		case seg.first == desc.min && seg.last+seg.step > desc.max:
+			items = append(items, "*/"+strconv.Itoa(seg.step))
+		default:
+			items = append(items, strconv.Itoa(seg.first)+"-"+strconv.Itoa(seg.last)+"/"+strconv.Itoa(seg.step))
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// segment is an arithmetic progression of values from first to last.
+type segment struct {
+	first, last, step int
+}
+
+// listSegments splits a sorted list of values into segments, by greedily
+// taking the longest arithmetic progression of at least 3 values starting at
+// each value. Values which are not part of such a progression are returned as
+// single-valued segments.
+func listSegments(list []int) []segment {
+	segments := make([]segment, 0, len(list))
+	for i := 0; i < len(list); {
+		j := i + 1
+		if j < len(list) {
+			step := list[j] - list[i]
+			for j+1 < len(list) && list[j+1]-list[j] == step {
+				j++
+			}
+			if j-i >= 2 {
+				segments = append(segments, segment{first: list[i], last: list[j], step: step})
+				i = j + 1
+				continue
+			}
+		}
+		segments = append(segments, segment{first: list[i], last: list[i], step: 1})
+		i++
+	}
+	return segments
+}
@@ -0,0 +1,166 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  CronFormat
+		expr    string
+		options []ParseOption
+		want    string
+	}{
+		{name: "wildcards", expr: "* * * * *", want: "0 * * * * * *"},
+		{name: "seconds", expr: "* * * * * * *", want: "* * * * * * *"},
+		{name: "predefined alias", expr: "@weekly", want: "0 0 0 * * 0 *"},
+		{name: "question marks", expr: "0 0 ? * ?", want: "0 0 0 * * * *"},
+		{name: "single values", expr: "5 4 3 2 1 2021", want: "0 5 4 3 2 1 2021"},
+		{name: "named values", expr: "0 0 * jan-mar mon,wed,fri", want: "0 0 0 * 1-3 1-5/2 *"},
+		{name: "range", expr: "0-29 * * * *", want: "0 0-29 * * * * *"},
+		{name: "wildcard interval", expr: "*/15 * * * *", want: "0 */15 * * * * *"},
+		{name: "just interval", expr: "/15 * * * *", want: "0 */15 * * * * *"},
+		{name: "value interval", expr: "5/15 * * * *", want: "0 5-50/15 * * * * *"},
+		{name: "range interval", expr: "17-43/5 * * * *", want: "0 17-42/5 * * * * *"},
+		{name: "range interval list", expr: "15-30/4,55 * * * *", want: "0 15-27/4,55 * * * * *"},
+		{name: "two values", expr: "0,30 * * * *", want: "0 0,30 * * * * *"},
+		{name: "overlapping directives", expr: "0-10,5-15 * * * *", want: "0 0-15 * * * * *"},
+		{name: "full range of minutes", expr: "0-59 * * * *", want: "0 * * * * * *"},
+		{name: "full range of days of month", expr: "0 0 1-31 * *", want: "0 0 0 1-31 * * *"},
+		{name: "full range of days of week", expr: "0 0 * * 0-6", want: "0 0 0 * * 0-6 *"},
+		{name: "sunday as 7", expr: "0 0 * * 7", want: "0 0 0 * * 0 *"},
+		{name: "days of month interval", expr: "0 0 */3 * *", want: "0 0 0 */3 * * *"},
+		{name: "last day of month", expr: "0 0 L * *", want: "0 0 0 L * * *"},
+		{name: "last work day of month", expr: "0 0 LW * *", want: "0 0 0 LW * * *"},
+		{name: "work day of month", expr: "0 0 15W,1,2 * *", want: "0 0 0 1,2,15W * * *"},
+		{name: "last day of week", expr: "0 0 * * 5L", want: "0 0 0 * * 5L *"},
+		{name: "specific day of week", expr: "0 0 * * 2#3,thu#1", want: "0 0 0 * * 4#1,2#3 *"},
+		{name: "mixed days of week", expr: "0 0 * * 1,5L,6#5", want: "0 0 0 * * 1,5L,6#5 *"},
+		{name: "days before last day of month", expr: "0 0 L-3,L-1,L * *", want: "0 0 0 L,L-1,L-3 * * *"},
+		{name: "work days before last day of month", expr: "0 0 L-2W,LW,L-10 * *", want: "0 0 0 L-10,LW,L-2W * * *"},
+		{name: "years", expr: "0 0 1 1 * 2000,2006,2008,2013-2015", want: "0 0 0 1 1 * 2000,2006,2008,2013-2015"},
+		{
+			name:   "days of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * 2-6 *",
+			want:   "0 0 11 ? * 2-6 *",
+		},
+		{
+			name:   "days of week interval with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 11 ? * */2 *",
+			want:   "0 0 11 ? * */2 *",
+		},
+		{
+			name:   "last day of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 15 10 ? * 6L *",
+			want:   "0 15 10 ? * 6L *",
+		},
+		{
+			name:   "specific day of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 15 10 ? * FRI#3 *",
+			want:   "0 15 10 ? * 6#3 *",
+		},
+		{
+			name:   "L as day of week with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 0 12 ? * L *",
+			want:   "0 0 12 ? * 7 *",
+		},
+		{
+			name:   "days before last day of month with CronFormatQuartz",
+			format: CronFormatQuartz,
+			expr:   "0 15 10 L-2 * ? *",
+			want:   "0 15 10 L-2 * ? *",
+		},
+		{name: "interval", expr: "@every 90m", want: "@every 1h30m0s"},
+		{name: "interval with phase", expr: "@every 1h 15m", want: "@every 1h0m0s 15m0s"},
+		{
+			name:    "interval with hashed phase",
+			expr:    "@every 1h H",
+			options: []ParseOption{WithHash("myid1")},
+			want:    "@every 1h0m0s 14m59s",
+		},
+		{
+			name:   "five fields with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "*/15 9-17 ? * mon-fri",
+			want:   "*/15 9-17 * * 1-5",
+		},
+		{
+			name:   "predefined alias with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "@midnight",
+			want:   "0 0 * * *",
+		},
+		{
+			name:   "time zone with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "TZ=Asia/Tokyo 0 9 * * *",
+			want:   "CRON_TZ=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name:   "interval with CronFormatKubernetes",
+			format: CronFormatKubernetes,
+			expr:   "@every 90m",
+			want:   "@every 1h30m0s",
+		},
+		{
+			name:    "hash",
+			expr:    "H H(0-7) * * *",
+			options: []ParseOption{WithHash("myid1")},
+			want:    "0 59 3 * * * *",
+		},
+		{
+			name:    "hash interval with WithHashEmptySeconds and WithHashFields",
+			expr:    "H/20 H(0-7) * * *",
+			options: []ParseOption{WithHash("myid1"), WithHashEmptySeconds(), WithHashFields()},
+			want:    "44 16-56/20 7 * * * *",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = CronFormatStandard
+			}
+			expr, err := ParseForFormat(format, tt.expr, tt.options...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.String())
+			assert.Equal(t, tt.expr, expr.Original())
+
+			// The normalized form should parse back to an equivalent Expression.
+			normalized, err := ParseForFormat(format, expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, normalized.String())
+			assertSameSchedule(t, expr, normalized)
+		})
+	}
+}
+
+func TestExpression_String_RoundTrip(t *testing.T) {
+	for _, test := range crontests {
+		test := test
+		t.Run(test.expr, func(t *testing.T) {
+			expr := MustParse(test.expr)
+			normalized, err := Parse(expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, expr.String(), normalized.String())
+			assertSameSchedule(t, expr, normalized)
+		})
+	}
+}
+
+// assertSameSchedule checks that both expressions give the same next time instants.
+func assertSameSchedule(t *testing.T, expected, actual *Expression) {
+	from := time.Date(2013, time.August, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, expected.NextN(from, 100), actual.NextN(from, 100))
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -211,6 +212,30 @@ var crontests = []crontest{
 		},
 	},
 
+	// Days before last day of month
+	{
+		"0 18 L-2 * *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-09-02 00:00:00", "Sat 2013-09-28 18:00"},
+			{"2013-11-02 00:00:00", "Thu 2013-11-28 18:00"},
+			{"2014-02-01 00:00:00", "Wed 2014-02-26 18:00"},
+			{"2016-02-01 00:00:00", "Sat 2016-02-27 18:00"},
+		},
+	},
+
+	// Work days nearest to days before last day of month
+	{
+		"0 18 L-2W * *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-09-02 00:00:00", "Fri 2013-09-27 18:00"},
+			{"2013-11-02 00:00:00", "Thu 2013-11-28 18:00"},
+			{"2014-09-01 00:00:00", "Mon 2014-09-29 18:00"},
+			{"2016-02-01 00:00:00", "Fri 2016-02-26 18:00"},
+		},
+	},
+
 	// Zero padded months
 	{
 		"0 0 * 04 * *",
@@ -222,6 +247,30 @@ var crontests = []crontest{
 		},
 	},
 
+	// Leap days beyond 2099
+	{
+		"0 0 29 2 *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2096-03-01 00:00:00", "Fri 2104-02-29 00:00"},
+			{"2399-01-01 00:00:00", "Tue 2400-02-29 00:00"},
+			{"9990-01-01 00:00:00", "Sat 9992-02-29 00:00"},
+		},
+	},
+
+	// Years before 1970 and after 2099
+	{
+		"0 0 0 1 1 * 1066,1900-1902,2100/100",
+		"2006-01-02 15:04:05",
+		[]crontimes{
+			{"1000-06-01 00:00:00", "1066-01-01 00:00:00"},
+			{"1066-06-01 00:00:00", "1900-01-01 00:00:00"},
+			{"1901-06-01 00:00:00", "1902-01-01 00:00:00"},
+			{"2013-06-01 00:00:00", "2100-01-01 00:00:00"},
+			{"9800-01-01 00:00:00", "9900-01-01 00:00:00"},
+		},
+	},
+
 	// TODO: more tests
 }
 
@@ -256,6 +305,11 @@ func TestZero(t *testing.T) {
 		t.Error(`("* * * * * 2050").Next("2013-08-31").IsZero() returned 'true', expected 'false'`)
 	}
 
+	next = MustParse("0 0 29 2 *").Next(time.Date(9996, time.March, 1, 0, 0, 0, 0, time.UTC))
+	if next.IsZero() == false {
+		t.Error(`("0 0 29 2 *").Next("9996-03-01").IsZero() returned 'false', expected 'true'`)
+	}
+
 	next = MustParse("* * * * * 2099").Next(time.Time{})
 	if next.IsZero() == false {
 		t.Error(`("* * * * * 2014").Next(time.Time{}).IsZero() returned 'true', expected 'false'`)
@@ -337,7 +391,7 @@ func TestInvalidExpr(t *testing.T) {
 		{
 			_, err := Parse(test.expr)
 			if assert.Error(t, err) {
-				assert.Equal(t, test.err, err)
+				assert.EqualError(t, err, test.err.Error())
 			}
 		}
 	}
@@ -1145,6 +1199,301 @@ func TestNext_DaylightSaving_AdditionalTests(t *testing.T) {
 	}
 }
 
+/******************************************************************************/
+
+var crontestsPrev = []crontest{
+	// Seconds
+	{
+		"* * * * * * *",
+		"2006-01-02 15:04:05",
+		[]crontimes{
+			{"2013-01-01 00:00:01", "2013-01-01 00:00:00"},
+			{"2013-01-01 00:01:00", "2013-01-01 00:00:59"},
+			{"2013-01-01 01:00:00", "2013-01-01 00:59:59"},
+			{"2013-01-02 00:00:00", "2013-01-01 23:59:59"},
+			{"2013-03-01 00:00:00", "2013-02-28 23:59:59"},
+			{"2016-03-01 00:00:00", "2016-02-29 23:59:59"},
+			{"2013-01-01 00:00:00", "2012-12-31 23:59:59"},
+		},
+	},
+
+	// Leap days beyond 2099
+	{
+		"0 0 29 2 *",
+		"2006-01-02 15:04:05",
+		[]crontimes{
+			{"2104-01-01 00:00:00", "2096-02-29 00:00:00"},
+			{"2400-03-01 00:00:00", "2400-02-29 00:00:00"},
+			{"9999-12-31 23:59:59", "9996-02-29 00:00:00"},
+		},
+	},
+
+	// Minutes with interval
+	{
+		"17-43/5 * * * *",
+		"2006-01-02 15:04:05",
+		[]crontimes{
+			{"2013-01-01 00:17:00", "2012-12-31 23:42:00"},
+			{"2013-01-01 00:17:01", "2013-01-01 00:17:00"},
+			{"2013-01-01 00:30:00", "2013-01-01 00:27:00"},
+			{"2013-01-01 00:50:00", "2013-01-01 00:42:00"},
+			{"2013-03-01 00:10:00", "2013-02-28 23:42:00"},
+			{"2016-03-01 00:10:00", "2016-02-29 23:42:00"},
+		},
+	},
+
+	// Days of week
+	{
+		"0 0 * * MON",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-01-01 00:00:00", "Mon 2012-12-31 00:00"},
+			{"2013-02-04 00:00:00", "Mon 2013-01-28 00:00"},
+			{"2013-02-04 00:00:01", "Mon 2013-02-04 00:00"},
+		},
+	},
+
+	// Specific days of week
+	{
+		"0 0 * * 6#5",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-11-30 00:00:00", "Sat 2013-08-31 00:00"},
+			{"2014-03-01 00:00:00", "Sat 2013-11-30 00:00"},
+		},
+	},
+
+	// Last days of week
+	{
+		"0 0 * * 5L",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-09-01 00:00:00", "Fri 2013-08-30 00:00"},
+			{"2013-09-27 00:00:00", "Fri 2013-08-30 00:00"},
+			{"2013-09-28 00:00:00", "Fri 2013-09-27 00:00"},
+		},
+	},
+
+	// Work day of month
+	{
+		"0 0 14W * *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-05-01 00:00:00", "Mon 2013-04-15 00:00"},
+			{"2013-09-14 00:00:00", "Fri 2013-09-13 00:00"},
+		},
+	},
+
+	// Last day of month
+	{
+		"0 0 L * *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-10-02 00:00:00", "Mon 2013-09-30 00:00"},
+			{"2014-03-01 00:00:00", "Fri 2014-02-28 00:00"},
+			{"2016-03-15 00:00:00", "Mon 2016-02-29 00:00"},
+		},
+	},
+
+	// Last work day of month
+	{
+		"0 0 LW * *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2013-10-02 00:00:00", "Mon 2013-09-30 00:00"},
+			{"2013-12-01 00:00:00", "Fri 2013-11-29 00:00"},
+		},
+	},
+
+	// Leap day
+	{
+		"0 0 29 2 *",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2019-01-01 00:00:00", "Mon 2016-02-29 00:00"},
+			{"2016-02-29 00:00:00", "Wed 2012-02-29 00:00"},
+		},
+	},
+
+	// Years
+	{
+		"0 30 12 1 6 * 2010-2012",
+		"Mon 2006-01-02 15:04",
+		[]crontimes{
+			{"2020-01-01 00:00:00", "Fri 2012-06-01 12:30"},
+			{"2012-06-01 12:30:00", "Wed 2011-06-01 12:30"},
+		},
+	},
+}
+
+func TestExpressionsPrev(t *testing.T) {
+	for _, test := range crontestsPrev {
+		for _, times := range test.times {
+			from, _ := time.Parse("2006-01-02 15:04:05", times.from)
+			expr, err := Parse(test.expr)
+			if err != nil {
+				t.Errorf(`Parse("%s") returned "%s"`, test.expr, err.Error())
+			}
+			prev := expr.Prev(from)
+			prevstr := prev.Format(test.layout)
+			if prevstr != times.next {
+				t.Errorf(`("%s").Prev("%s") = "%s", got "%s"`, test.expr, times.from, times.next, prevstr)
+			}
+		}
+	}
+}
+
+func TestPrev_Zero(t *testing.T) {
+	from, _ := time.Parse("2006-01-02", "2013-08-31")
+	assert.True(t, MustParse("* * * * * 2050").Prev(from).IsZero())
+	assert.False(t, MustParse("* * * * * 1980").Prev(from).IsZero())
+	assert.True(t, MustParse("* * * * *").Prev(time.Time{}).IsZero())
+	assert.True(t, MustParse("0 0 1 1 * 2000").Prev(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestPrev_Nanoseconds(t *testing.T) {
+	from := time.Date(2013, time.August, 31, 10, 0, 0, 500, time.UTC)
+	assert.Equal(t, time.Date(2013, time.August, 31, 10, 0, 0, 0, time.UTC),
+		MustParse("* * * * *").Prev(from))
+}
+
+func TestPrevN(t *testing.T) {
+	from, _ := time.Parse("2006-01-02 15:04:05", "2014-11-29 00:00:01")
+	result := MustParse("0 0 * * 6#5").PrevN(from, 5)
+	expected := []string{
+		"Sat, 29 Nov 2014 00:00:00",
+		"Sat, 30 Aug 2014 00:00:00",
+		"Sat, 31 May 2014 00:00:00",
+		"Sat, 29 Mar 2014 00:00:00",
+		"Sat, 30 Nov 2013 00:00:00",
+	}
+	require.Len(t, result, len(expected))
+	for i, prev := range result {
+		assert.Equal(t, expected[i], prev.Format("Mon, 2 Jan 2006 15:04:05"))
+	}
+
+	assert.Len(t, MustParse("* * * * * * 2013").PrevN(time.Date(2013, 1, 1, 0, 0, 3, 0, time.UTC), 5), 3)
+}
+
+// TestPrev_ReversesNext checks that walking backwards with Prev visits exactly
+// the instants visited by Next, including around daylight saving transitions.
+func TestPrev_ReversesNext(t *testing.T) {
+	type location struct {
+		name  string
+		times []time.Time
+	}
+	var locations []location
+	for name, dates := range map[string][][3]int{
+		"America/Los_Angeles": {{2019, 3, 9}, {2019, 11, 2}},
+		"Australia/Lord_Howe": {{2019, 4, 6}, {2019, 10, 5}},
+		"America/Sao_Paulo":   {{2018, 2, 16}, {2018, 11, 3}},
+		"America/Santiago":    {{2021, 9, 3}, {2022, 4, 1}},
+	} {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+		l := location{name: name}
+		for _, d := range dates {
+			l.times = append(l.times, time.Date(d[0], time.Month(d[1]), d[2], 0, 0, 0, 0, loc))
+		}
+		locations = append(locations, l)
+	}
+
+	cronExprs := []string{
+		"* * * * *",
+		"*/7 * * * *",
+		"0 2 * * *",
+		"* 1 * * *",
+		"30 1 * * *",
+		"35 1 * * *",
+		"0 0 * * *",
+		"5 23 * * *",
+		"15 */2 * * *",
+		"50 10 * * * * *",
+		"0 0/15 * * * * *",
+	}
+
+	for _, cron := range cronExprs {
+		expr := MustParse(cron)
+		for _, l := range locations {
+			for _, init := range l.times {
+				t.Run(fmt.Sprintf("%v: %v", cron, init), func(t *testing.T) {
+					var nexts []time.Time
+					for next := expr.Next(init); next.Before(init.Add(72 * time.Hour)); next = expr.Next(next) {
+						nexts = append(nexts, next)
+					}
+					require.NotEmpty(t, nexts)
+
+					last := nexts[len(nexts)-1]
+					prevs := expr.PrevN(last, uint(len(nexts)-1))
+					require.Len(t, prevs, len(nexts)-1)
+					for i, prev := range prevs {
+						want := nexts[len(nexts)-2-i]
+						if !prev.Equal(want) {
+							t.Fatalf("prev(%v) = %v, want %v", nexts[len(nexts)-1-i], prev, want)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+// TestConcurrentEvaluation shares parsed expressions across goroutines, and is
+// meant to be run with -race.
+func TestConcurrentEvaluation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, loc)
+
+	exprs := []*Expression{
+		MustParse("0 0 * * 6#5"),
+		MustParse("0 0 L * *"),
+		MustParse("30 3 15W 3/3 *"),
+		MustParse("0 0/2 ? * *"),
+		MustParse("0 0 0 * Feb-Nov/2 thu#3 2000-2050"),
+	}
+	hashed, err := ParseForFormat(CronFormatStandard, "H H * * *", WithHash("myid1"), WithHashEmptySeconds())
+	require.NoError(t, err)
+	exprs = append(exprs, hashed)
+
+	const n = 50
+	nexts := make([][]time.Time, len(exprs))
+	prevs := make([][]time.Time, len(exprs))
+	for i, expr := range exprs {
+		nexts[i] = expr.NextN(from, n)
+		prevs[i] = expr.PrevN(from, n)
+	}
+
+	const workers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*len(exprs))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, expr := range exprs {
+				next := from
+				for j := 0; j < n; j++ {
+					next = expr.Next(next)
+					if !next.Equal(nexts[i][j]) {
+						errs <- fmt.Errorf("expr %d: next #%d = %v, want %v", i, j, next, nexts[i][j])
+						return
+					}
+				}
+				if got := expr.PrevN(from, n); !assert.ObjectsAreEqual(prevs[i], got) {
+					errs <- fmt.Errorf("expr %d: PrevN = %v, want %v", i, got, prevs[i])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
 // Issue: https://github.com/gorhill/cronexpr/issues/16
 func TestInterval_Interval60Issue(t *testing.T) {
 	_, err := Parse("*/60 * * * * *")
@@ -1187,12 +1536,47 @@ func BenchmarkParse(b *testing.B) {
 	}
 }
 
+func TestParse_Years(t *testing.T) {
+	tests := []struct {
+		years string
+		want  []int
+	}{
+		{years: "*"},
+		{years: "*/1"},
+		{years: "1-9999"},
+		{years: "1-5000,4000-9999"},
+		{years: "2030,2020-2030/5,2025", want: []int{2020, 2025, 2030}},
+		{years: "9990/3", want: []int{9990, 9993, 9996, 9999}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.years, func(t *testing.T) {
+			expr, err := Parse("0 0 * * * " + tt.years)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.yearList)
+		})
+	}
+}
+
+func BenchmarkParse_Years(b *testing.B) {
+	for _, years := range []string{"*", "*/1", "1-9999", "*/2", "2000-2050"} {
+		cron := "0 0 * * * " + years
+		b.Run(years, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = MustParse(cron)
+			}
+		})
+	}
+}
+
 func BenchmarkNext(b *testing.B) {
 	exprs := make([]*Expression, benchmarkExpressionsLen)
 	for i := 0; i < benchmarkExpressionsLen; i++ {
 		exprs[i] = MustParse(benchmarkExpressions[i])
 	}
-	from := time.Now()
+	from := time.Date(2021, time.June, 15, 12, 34, 56, 0, time.UTC)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		expr := exprs[i%benchmarkExpressionsLen]
@@ -1203,3 +1587,23 @@ func BenchmarkNext(b *testing.B) {
 		next = expr.Next(next)
 	}
 }
+
+func BenchmarkNext_Expressions(b *testing.B) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(b, err)
+
+	for _, cron := range benchmarkExpressions {
+		expr := MustParse(cron)
+		for _, from := range []time.Time{
+			time.Date(2021, time.June, 15, 12, 34, 56, 0, time.UTC),
+			time.Date(2021, time.June, 15, 12, 34, 56, 0, newYork),
+		} {
+			b.Run(fmt.Sprintf("%s/%s", cron, from.Location()), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					expr.Next(from)
+				}
+			})
+		}
+	}
+}
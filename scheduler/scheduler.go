@@ -0,0 +1,299 @@
+// Package scheduler runs functions at the time instants of cron expressions,
+// or of any other cronexpr.Schedule, within the current process.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/furiko-io/cronexpr"
+)
+
+var (
+	// ErrJobExists is returned by Add when a job with the same ID is already
+	// registered.
+	ErrJobExists = errors.New("scheduler: job already exists")
+
+	// ErrJobNotFound is returned when no job with the given ID is registered.
+	ErrJobNotFound = errors.New("scheduler: job not found")
+
+	// ErrRunning is returned by Run when the Scheduler is already running.
+	ErrRunning = errors.New("scheduler: already running")
+)
+
+// JobFunc is the function of a job. It is called with the context given to
+// Run, and with the time instant of the schedule that it is run for, which may
+// be slightly earlier than the current time.
+type JobFunc func(ctx context.Context, firedAt time.Time)
+
+// Scheduler runs jobs at the time instants of their schedules. Jobs can be
+// added, removed, paused and resumed at any time, including while it is
+// running, and by multiple goroutines.
+//
+// Each run of a job is in a goroutine of its own, so that a long run does not
+// delay other jobs, and runs of the same job may overlap. The next run of a job
+// is the first time instant of its schedule following the one it was last run
+// for, rather than following the time it was actually started, so that runs do
+// not drift. If the Scheduler falls behind by more than one time instant, for
+// instance while the machine is suspended, the job is run once for the
+// earliest of them, and the others are skipped.
+type Scheduler struct {
+	clock cronexpr.Clock
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	queue   jobQueue
+	running bool
+
+	// wake is signaled when the earliest job in the queue may have changed.
+	wake chan struct{}
+}
+
+// Option configures a Scheduler.
+type Option func(s *Scheduler)
+
+// WithClock returns an Option that makes the Scheduler use the given Clock
+// rather than the time package, e.g. a cronexpr.FakeClock in tests.
+func WithClock(clock cronexpr.Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// New returns a Scheduler with no jobs. Call Run to start running jobs.
+func New(options ...Option) *Scheduler {
+	s := &Scheduler{
+		clock: cronexpr.RealClock{},
+		jobs:  make(map[string]*job),
+		wake:  make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+type job struct {
+	id       string
+	schedule cronexpr.Schedule
+	fn       JobFunc
+	paused   bool
+
+	// next is the time instant the job is run for next, or a zero value if
+	// its schedule has no more time instants.
+	next time.Time
+
+	// index is the index of the job in the queue, or -1 if it is not in it.
+	index int
+}
+
+// Add registers a job with the given ID, which is run at the time instants of
+// `schedule` following the current time, such as those of an
+// *cronexpr.Expression.
+func (s *Scheduler) Add(id string, schedule cronexpr.Schedule, fn JobFunc) error {
+	if schedule == nil || fn == nil {
+		return errors.New("scheduler: nil schedule or function")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; ok {
+		return ErrJobExists
+	}
+	j := &job{id: id, schedule: schedule, fn: fn, index: -1}
+	s.jobs[id] = j
+	s.schedule(j, s.clock.Now())
+	return nil
+}
+
+// Remove unregisters the job with the given ID. Runs of the job which have
+// already started are not stopped.
+func (s *Scheduler) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, id)
+	s.unschedule(j)
+	return nil
+}
+
+// Pause stops running the job with the given ID until it is resumed.
+func (s *Scheduler) Pause(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+	j.paused = true
+	s.unschedule(j)
+	return nil
+}
+
+// Resume runs the job with the given ID again at the time instants of its
+// schedule following the current time. The time instants it was paused for
+// are skipped.
+func (s *Scheduler) Resume(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if j.paused {
+		j.paused = false
+		s.schedule(j, s.clock.Now())
+	}
+	return nil
+}
+
+// Next returns the time instant that the job with the given ID is run for
+// next, or a zero value if it is paused or its schedule has no more time
+// instants, and false if there is no such job.
+func (s *Scheduler) Next(id string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	return j.next, true
+}
+
+// Run runs the jobs until `ctx` is done, and then waits for the runs which have
+// started to return. Jobs are given `ctx`, so that they can stop early.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return ErrRunning
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	for {
+		s.mu.Lock()
+		// The queue is looked at below, so that earlier changes need no
+		// further wake-up.
+		select {
+		case <-s.wake:
+		default:
+		}
+		now := s.clock.Now()
+		for len(s.queue) > 0 && !s.queue[0].next.After(now) {
+			j := s.queue[0]
+			firedAt := j.next
+			wg.Add(1)
+			go func(fn JobFunc) {
+				defer wg.Done()
+				fn(ctx, firedAt)
+			}(j.fn)
+
+			j.next = j.schedule.Next(firedAt)
+			if !j.next.IsZero() && !j.next.After(now) {
+				j.next = j.schedule.Next(now)
+			}
+			if j.next.IsZero() {
+				heap.Remove(&s.queue, j.index)
+			} else {
+				heap.Fix(&s.queue, j.index)
+			}
+		}
+
+		var timer cronexpr.Timer
+		var fire <-chan time.Time
+		if len(s.queue) > 0 {
+			timer = s.clock.NewTimer(s.queue[0].next.Sub(now))
+			fire = timer.C()
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-fire:
+		case <-s.wake:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// schedule queues the job for the first time instant of its schedule
+// following `now`. It must be called with s.mu held.
+func (s *Scheduler) schedule(j *job, now time.Time) {
+	j.next = j.schedule.Next(now)
+	if j.paused || j.next.IsZero() {
+		return
+	}
+	heap.Push(&s.queue, j)
+	if j.index == 0 {
+		s.notify()
+	}
+}
+
+// unschedule removes the job from the queue. It must be called with s.mu held.
+func (s *Scheduler) unschedule(j *job) {
+	if j.index >= 0 {
+		heap.Remove(&s.queue, j.index)
+		s.notify()
+	}
+	j.next = time.Time{}
+}
+
+// notify wakes Run up, if it is running, to look at the queue again.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jobQueue is a min-heap of jobs ordered by their next time instant.
+type jobQueue []*job
+
+func (q jobQueue) Len() int {
+	return len(q)
+}
+
+func (q jobQueue) Less(i, j int) bool {
+	return q[i].next.Before(q[j].next)
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	j := old[len(old)-1]
+	old[len(old)-1] = nil
+	j.index = -1
+	*q = old[:len(old)-1]
+	return j
+}
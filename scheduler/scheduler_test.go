@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/furiko-io/cronexpr"
+)
+
+type run struct {
+	id      string
+	firedAt string
+}
+
+func TestScheduler(t *testing.T) {
+	clock := cronexpr.NewFakeClock(at("00:40"))
+	s := New(WithClock(clock))
+	runs := make(chan run, 100)
+	record := func(id string) JobFunc {
+		return func(ctx context.Context, firedAt time.Time) {
+			runs <- run{id: id, firedAt: firedAt.Format("15:04")}
+		}
+	}
+	require.NoError(t, s.Add("hourly", cronexpr.MustParse("0 * * * *"), record("hourly")))
+	require.NoError(t, s.Add("quarter", cronexpr.MustParse("15,45 * * * *"), record("quarter")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	// Each run is in a goroutine of its own, so it is received before the
+	// clock is advanced again.
+	clock.BlockUntilTimer(at("00:45"))
+	clock.Advance(5 * time.Minute)
+	assert.Equal(t, run{"quarter", "00:45"}, <-runs)
+	clock.BlockUntilTimer(at("01:00"))
+	clock.Advance(15 * time.Minute)
+	assert.Equal(t, run{"hourly", "01:00"}, <-runs)
+	clock.BlockUntilTimer(at("01:15"))
+
+	// Paused jobs skip their time instants.
+	require.NoError(t, s.Pause("quarter"))
+	clock.BlockUntilTimer(at("02:00"))
+	next, ok := s.Next("quarter")
+	assert.True(t, ok)
+	assert.True(t, next.IsZero())
+	clock.Advance(time.Hour)
+	assert.Equal(t, run{"hourly", "02:00"}, <-runs)
+	clock.BlockUntilTimer(at("03:00"))
+	require.NoError(t, s.Resume("quarter"))
+	clock.BlockUntilTimer(at("02:15"))
+	clock.Advance(15 * time.Minute)
+	assert.Equal(t, run{"quarter", "02:15"}, <-runs)
+	clock.BlockUntilTimer(at("02:45"))
+
+	require.NoError(t, s.Remove("quarter"))
+	clock.BlockUntilTimer(at("03:00"))
+	_, ok = s.Next("quarter")
+	assert.False(t, ok)
+
+	// A job whose schedule ends is run for its last time instant only.
+	require.NoError(t, s.Add("once", cronexpr.MustParse("0 30 2 1 3 * 2021"), record("once")))
+	clock.BlockUntilTimer(at("02:30"))
+	clock.Advance(15 * time.Minute)
+	assert.Equal(t, run{"once", "02:30"}, <-runs)
+	clock.BlockUntilTimer(at("03:00"))
+	next, ok = s.Next("once")
+	assert.True(t, ok)
+	assert.True(t, next.IsZero())
+
+	cancel()
+	assert.NoError(t, <-done)
+	close(runs)
+	var got []run
+	for r := range runs {
+		got = append(got, r)
+	}
+	assert.Empty(t, got)
+}
+
+func TestScheduler_FallingBehind(t *testing.T) {
+	clock := cronexpr.NewFakeClock(at("00:40"))
+	s := New(WithClock(clock))
+	runs := make(chan time.Time, 100)
+	require.NoError(t, s.Add("hourly", cronexpr.MustParse("0 * * * *"), func(ctx context.Context, firedAt time.Time) {
+		runs <- firedAt
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	clock.BlockUntilTimer(at("01:00"))
+	clock.Advance(3*time.Hour + 30*time.Minute)
+	clock.BlockUntilTimer(at("05:00"))
+
+	cancel()
+	assert.NoError(t, <-done)
+	close(runs)
+	var got []string
+	for firedAt := range runs {
+		got = append(got, firedAt.Format("15:04"))
+	}
+	assert.Equal(t, []string{"01:00"}, got)
+}
+
+func TestScheduler_Shutdown(t *testing.T) {
+	clock := cronexpr.NewFakeClock(at("00:40"))
+	s := New(WithClock(clock))
+	started := make(chan struct{})
+	var stopped bool
+	require.NoError(t, s.Add("hourly", cronexpr.MustParse("0 * * * *"), func(ctx context.Context, firedAt time.Time) {
+		close(started)
+		<-ctx.Done()
+		stopped = true
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	clock.BlockUntilTimer(at("01:00"))
+	clock.Advance(20 * time.Minute)
+	<-started
+	cancel()
+
+	// Run waits for the running job to return.
+	assert.NoError(t, <-done)
+	assert.True(t, stopped)
+}
+
+func TestScheduler_Errors(t *testing.T) {
+	clock := cronexpr.NewFakeClock(at("00:40"))
+	s := New(WithClock(clock))
+	expr := cronexpr.MustParse("0 * * * *")
+	fn := func(ctx context.Context, firedAt time.Time) {}
+	require.NoError(t, s.Add("job", expr, fn))
+	assert.Equal(t, ErrJobExists, s.Add("job", expr, fn))
+	assert.Error(t, s.Add("nil", nil, fn))
+	assert.Error(t, s.Add("nil", expr, nil))
+	assert.Equal(t, ErrJobNotFound, s.Remove("other"))
+	assert.Equal(t, ErrJobNotFound, s.Pause("other"))
+	assert.Equal(t, ErrJobNotFound, s.Resume("other"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+	clock.BlockUntilTimer(at("01:00"))
+	assert.Equal(t, ErrRunning, s.Run(ctx))
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// at returns the time at `hm`, given as `15:04`, on March 1, 2021 in UTC, which
+// the tests start on.
+func at(hm string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", "2021-03-01 "+hm)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}